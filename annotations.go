@@ -0,0 +1,132 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const (
+	annotationsPath      = "/annotations"
+	annotationsStateFile = "item-annotations.json"
+	maxNoteLength        = 4000
+)
+
+// annotationsState maps item identities to annotations. Annotations are
+// shared by all users that can see the item.
+type annotationsState struct {
+	Items map[string]db.Annotation `json:"items"`
+}
+
+func (a *App) loadAnnotations() (annotations map[string]db.Annotation, err error) {
+	var state annotationsState
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+	if err = loadState(a.statePath(annotationsStateFile), &state); err != nil {
+		return nil, err
+	}
+	return state.Items, nil
+}
+
+// updateAnnotation applies update to the annotation stored under key and
+// returns the result; empty annotations are removed.
+func (a *App) updateAnnotation(key string, update func(db.Annotation) db.Annotation) (annotation db.Annotation, err error) {
+	path := a.statePath(annotationsStateFile)
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+	var state annotationsState
+	if err = loadState(path, &state); err != nil {
+		return annotation, err
+	}
+	if state.Items == nil {
+		state.Items = make(map[string]db.Annotation)
+	}
+	annotation = update(state.Items[key])
+	if annotation.IsZero() {
+		delete(state.Items, key)
+	} else {
+		state.Items[key] = annotation
+	}
+	return annotation, saveState(path, state)
+}
+
+// annotate returns items with their stored annotations attached, leaving
+// the shared slice untouched.
+func (a *App) annotate(items []db.Item) []db.Item {
+	annotations, err := a.loadAnnotations()
+	if err != nil {
+		slog.Warn("load item annotations failed", "err", err)
+		return items
+	}
+	if len(annotations) == 0 {
+		return items
+	}
+	annotated := make([]db.Item, len(items))
+	for index, item := range items {
+		item.Annotation = annotations[item.Identity()]
+		annotated[index] = item
+	}
+	return annotated
+}
+
+// handleAnnotations shows and saves the note, tags and favorite flag of one
+// visible item. A POST with action "favorite" toggles the flag and answers
+// htmx requests with the refreshed star.
+func (a *App) handleAnnotations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	items, _ := a.visibleItemsAndHolders(r)
+	key := r.FormValue("key")
+	item, found := db.NewItemIndex(items).Find(key)
+	if !found {
+		http.Error(w, "unknown item", http.StatusNotFound)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		update := func(db.Annotation) db.Annotation {
+			return db.Annotation{
+				Note:     strings.TrimSpace(r.PostFormValue("note")),
+				Tags:     db.ParseTags(r.PostFormValue("tags")),
+				Favorite: r.PostFormValue("favorite") != "",
+			}
+		}
+		if r.PostFormValue("action") == "favorite" {
+			update = func(existing db.Annotation) db.Annotation {
+				existing.Favorite = !existing.Favorite
+				return existing
+			}
+		}
+		if len(r.PostFormValue("note")) > maxNoteLength {
+			http.Error(w, "note is too long", http.StatusBadRequest)
+			return
+		}
+		annotation, err := a.updateAnnotation(key, update)
+		if err != nil {
+			slog.Error("save item annotation failed", "err", err)
+			http.Error(w, "failed to save annotation", http.StatusInternalServerError)
+			return
+		}
+		if r.Header.Get("HX-Request") != "" {
+			item.Annotation = annotation
+			if err = templates.FavoriteButton(item).Render(w); err != nil {
+				slog.Error("render favorite button failed", "err", err)
+			}
+			return
+		}
+		http.Redirect(w, r, annotationsPath+"?"+url.Values{"key": {key}}.Encode(), http.StatusSeeOther)
+		return
+	}
+
+	if err := templates.Annotation(item).Render(w); err != nil {
+		slog.Error("render annotation failed", "err", err)
+		http.Error(w, "failed to render annotation", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"gotest.tools/v3/assert"
+)
+
+func TestAnnotations(t *testing.T) {
+	items := []db.Item{
+		{ItemID: 7, Name: "Ring of Thirty", ItemType: "Jewelry", CharacterName: "CharA", Server: "Argonnessen", MinimumLevel: 30},
+		{ItemID: 8, Name: "Sword", ItemType: "Weapon", CharacterName: "CharA", Server: "Argonnessen", MinimumLevel: 30},
+	}
+	app := newTestApp(t, items)
+	handler := app.routes()
+
+	post := func(form url.Values, htmx bool) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest("POST", "/annotations", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if htmx {
+			request.Header.Set("HX-Request", "true")
+		}
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+	get := func(target string) string {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", target, nil))
+		assert.Equal(t, recorder.Code, 200)
+		return recorder.Body.String()
+	}
+
+	recorder := post(url.Values{"key": {"Argonnessen/7"}, "tags": {"sell, guild loan"}, "note": {"ask Bob first"}}, false)
+	assert.Equal(t, recorder.Code, 303)
+	assert.Equal(t, recorder.Header().Get("Location"), "/annotations?key=Argonnessen%2F7")
+	assert.Assert(t, strings.Contains(get("/annotations?key=Argonnessen%2F7"), "ask Bob first</textarea>"))
+
+	recorder = post(url.Values{"key": {"Argonnessen/8"}, "action": {"favorite"}}, true)
+	assert.Equal(t, recorder.Code, 200)
+	assert.Assert(t, strings.Contains(recorder.Body.String(), "★"))
+
+	recorder = post(url.Values{"key": {"Argonnessen/9"}, "tags": {"sell"}}, false)
+	assert.Equal(t, recorder.Code, 404)
+
+	body := get("/")
+	assert.Assert(t, strings.Contains(body, `<span class="item-tag">guild loan</span>`))
+	assert.Assert(t, strings.Contains(body, `<option value="sell">sell</option>`))
+
+	body = get("/items?tag=sell")
+	assert.Assert(t, strings.Contains(body, "Ring of Thirty") && !strings.Contains(body, "Sword"))
+	body = get("/items?favorites=1")
+	assert.Assert(t, strings.Contains(body, "Sword") && !strings.Contains(body, "Ring of Thirty"))
+	body = get("/items?name_search=bob")
+	assert.Assert(t, strings.Contains(body, "Found 1 items."))
+
+	// Clearing everything drops the stored entry.
+	recorder = post(url.Values{"key": {"Argonnessen/7"}}, false)
+	assert.Equal(t, recorder.Code, 303)
+	annotations, err := app.loadAnnotations()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, annotations, map[string]db.Annotation{"Argonnessen/8": {Favorite: true}})
+}
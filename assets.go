@@ -0,0 +1,108 @@
+package main
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const (
+	staticDirName       = "static"
+	assetVersionParam   = "v"
+	assetHashLength     = 12
+	immutableCacheValue = "public, max-age=31536000, immutable"
+	revalidateCache     = "no-cache"
+)
+
+//go:embed static
+var embeddedStatic embed.FS
+
+// staticAssets serves the files under static/, either embedded in the binary
+// or from a development directory. URLs carry a content hash so browsers may
+// cache embedded assets forever.
+type staticAssets struct {
+	fsys   fs.FS
+	hashes map[string]string
+	dev    bool
+}
+
+func newStaticAssets(dir string) (assets *staticAssets, err error) {
+	assets = &staticAssets{hashes: map[string]string{}}
+	if dir != "" {
+		assets.fsys = os.DirFS(dir)
+		assets.dev = true
+		return assets, nil
+	}
+
+	if assets.fsys, err = fs.Sub(embeddedStatic, staticDirName); err != nil {
+		return nil, fmt.Errorf("open embedded static files: %w", err)
+	}
+	err = fs.WalkDir(assets.fsys, ".", func(name string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil || entry.IsDir() {
+			return walkErr
+		}
+		data, readErr := fs.ReadFile(assets.fsys, name)
+		if readErr != nil {
+			return fmt.Errorf("read %q: %w", name, readErr)
+		}
+		sum := sha256.Sum256(data)
+		assets.hashes[name] = hex.EncodeToString(sum[:])[:assetHashLength]
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("hash embedded static files: %w", err)
+	}
+	return assets, nil
+}
+
+// urls maps asset names to the URLs templates should reference. In
+// development mode only files present in the directory are listed, unhashed.
+func (s *staticAssets) urls() map[string]string {
+	urls := make(map[string]string)
+	if s.dev {
+		_ = fs.WalkDir(s.fsys, ".", func(name string, entry fs.DirEntry, walkErr error) error {
+			if walkErr == nil && !entry.IsDir() {
+				urls[name] = staticPathPrefix + name
+			}
+			return nil
+		})
+		return urls
+	}
+	for name, hash := range s.hashes {
+		urls[name] = staticPathPrefix + name + "?" + assetVersionParam + "=" + hash
+	}
+	return urls
+}
+
+func (s *staticAssets) handler() http.Handler {
+	fileServer := http.FileServerFS(s.fsys)
+	return http.StripPrefix(staticPathPrefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hash, known := s.hashes[path.Clean(r.URL.Path)]
+		switch {
+		case !known:
+			w.Header().Set("Cache-Control", revalidateCache)
+		case r.URL.Query().Get(assetVersionParam) == hash:
+			w.Header().Set("Cache-Control", immutableCacheValue)
+			w.Header().Set("ETag", `"`+hash+`"`)
+		default:
+			w.Header().Set("Cache-Control", revalidateCache)
+			w.Header().Set("ETag", `"`+hash+`"`)
+		}
+		fileServer.ServeHTTP(w, r)
+	}))
+}
+
+func (a *App) useStaticAssets(dir string) (err error) {
+	if a.assets, err = newStaticAssets(dir); err != nil {
+		return err
+	}
+	templates.SetAssetURLs(a.assets.urls())
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestEmbeddedStaticAssets(t *testing.T) {
+	assets, err := newStaticAssets("")
+	assert.NilError(t, err)
+	styleURL := assets.urls()["style.css"]
+	assert.Assert(t, strings.HasPrefix(styleURL, staticPathPrefix+"style.css?v="))
+
+	testCases := []struct {
+		name        string
+		path        string
+		expectCode  int
+		expectCache string
+	}{
+		{name: "hashed url", path: styleURL, expectCode: http.StatusOK, expectCache: immutableCacheValue},
+		{name: "unhashed url", path: staticPathPrefix + "style.css", expectCode: http.StatusOK, expectCache: revalidateCache},
+		{name: "missing file", path: staticPathPrefix + "missing.css", expectCode: http.StatusNotFound, expectCache: ""},
+	}
+
+	handler := assets.handler()
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, testCase.path, nil))
+			assert.Equal(t, recorder.Code, testCase.expectCode)
+			assert.Equal(t, recorder.Header().Get("Cache-Control"), testCase.expectCache)
+		})
+	}
+}
+
+// TestHTMXScript checks that pages load the embedded htmx copy when it has
+// been vendored, with an SRI hash matching it, and the CDN otherwise.
+func TestHTMXScript(t *testing.T) {
+	app := newTestApp(t, nil)
+	recorder := httptest.NewRecorder()
+	app.routes().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := recorder.Body.String()
+
+	data, err := fs.ReadFile(embeddedStatic, staticDirName+"/vendor/htmx.min.js")
+	if errors.Is(err, fs.ErrNotExist) {
+		assert.Assert(t, strings.Contains(body, `<script src="https://unpkg.com/htmx.org@`), body)
+		return
+	}
+	assert.NilError(t, err)
+	sum := sha512.Sum384(data)
+	assert.Assert(t, strings.Contains(body, `<script src="`+staticPathPrefix+`vendor/htmx.min.js?v=`), body)
+	assert.Assert(t, strings.Contains(body, `integrity="sha384-`+base64.StdEncoding.EncodeToString(sum[:])+`"`), body)
+}
+
+func TestStaticDirOverride(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "style.css"), []byte("body{}"), 0o600))
+
+	assets, err := newStaticAssets(dir)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, assets.urls(), map[string]string{"style.css": staticPathPrefix + "style.css"})
+
+	recorder := httptest.NewRecorder()
+	assets.handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, staticPathPrefix+"style.css", nil))
+	assert.Equal(t, recorder.Code, http.StatusOK)
+	assert.Equal(t, recorder.Body.String(), "body{}")
+	assert.Equal(t, recorder.Header().Get("Cache-Control"), revalidateCache)
+}
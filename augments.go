@@ -0,0 +1,57 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const augmentsPath = "/augments"
+
+func (a *App) handleAugments(w http.ResponseWriter, r *http.Request) {
+	items, _ := a.visibleItemsAndHolders(r)
+
+	query := r.URL.Query()
+	maxLevel := 0
+	if maxLevelStr := query.Get("max_level"); maxLevelStr != "" {
+		if value, convErr := strconv.Atoi(maxLevelStr); convErr == nil && value >= 0 {
+			maxLevel = value
+		}
+	}
+
+	var gearItems []db.Item
+	for _, item := range items {
+		if db.HasOpenAugmentSlot(item) {
+			gearItems = append(gearItems, item)
+		}
+	}
+	sort.SliceStable(gearItems, func(i, j int) bool {
+		return gearItems[i].Name < gearItems[j].Name
+	})
+
+	var selected *db.Item
+	var plans []db.AugmentSlotPlan
+	// Copies sharing an identity are identical, so any of them can be planned.
+	if key := query.Get("key"); key != "" {
+		for index := range items {
+			if items[index].Identity() == key && len(items[index].AugmentSlots) > 0 {
+				selected = &items[index]
+				break
+			}
+		}
+		if selected == nil {
+			http.Error(w, "item not found", http.StatusNotFound)
+			return
+		}
+		plans = db.PlanAugmentSlots(*selected, items, maxLevel)
+	}
+
+	if err := templates.Augments(gearItems, selected, plans, db.GroupAugmentsByColor(items), maxLevel).Render(w); err != nil {
+		slog.Error("render augments failed", "err", err)
+		http.Error(w, "failed to render augments", http.StatusInternalServerError)
+	}
+}
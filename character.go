@@ -0,0 +1,27 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const characterPath = "/character"
+
+func (a *App) handleCharacter(w http.ResponseWriter, r *http.Request) {
+	items, holders := a.visibleItemsAndHolders(r)
+
+	names := db.GetUniqueCharacterNames(items)
+	selected := r.URL.Query().Get("name")
+	var grids []db.ContainerGrid
+	if selected != "" {
+		grids = db.HolderContainers(items, holders, selected)
+	}
+
+	if err := templates.Character(names, selected, grids).Render(w); err != nil {
+		slog.Error("render character failed", "err", err)
+		http.Error(w, "failed to render character", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const clickiesPath = "/clickies"
+
+func (a *App) handleClickies(w http.ResponseWriter, r *http.Request) {
+	items, _ := a.visibleItemsAndHolders(r)
+	query := r.URL.Query()
+	spell := strings.TrimSpace(query.Get("spell"))
+	sortBy := query.Get("sort")
+	switch sortBy {
+	case db.ClickySortCharges, db.ClickySortChargesDesc:
+	default:
+		sortBy = db.ClickySortSpell
+	}
+
+	clickies := db.GroupItemsByClicky(items, spell, sortBy)
+	if err := templates.Clickies(clickies, spell, sortBy).Render(w); err != nil {
+		slog.Error("render clickies failed", "err", err)
+		http.Error(w, "failed to render clickies", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Annotation is what the player noted about an item. Annotations are kept
+// outside the Trove data, keyed by Item.Identity, and attached after loading.
+type Annotation struct {
+	Note     string   `json:"note,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Favorite bool     `json:"favorite,omitempty"`
+}
+
+func (a Annotation) IsZero() bool {
+	return a.Note == "" && len(a.Tags) == 0 && !a.Favorite
+}
+
+func (a Annotation) HasTag(tag string) bool {
+	return containsFold(a.Tags, tag)
+}
+
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(candidate string) bool {
+		return strings.EqualFold(candidate, value)
+	})
+}
+
+// ParseTags reads comma separated tags, dropping empty entries and
+// duplicates that differ only in case.
+func ParseTags(value string) []string {
+	var tags []string
+	for part := range strings.SplitSeq(value, ",") {
+		part = strings.Join(strings.Fields(part), " ")
+		if part == "" || containsFold(tags, part) {
+			continue
+		}
+		tags = append(tags, part)
+	}
+	return tags
+}
+
+// FilterTagged keeps the items carrying tag (any, for FilterAll) and, if
+// favoritesOnly is set, only favorites.
+func FilterTagged(items []Item, tag string, favoritesOnly bool) []Item {
+	anyTag := tag == "" || tag == FilterAll
+	if anyTag && !favoritesOnly {
+		return items
+	}
+	var tagged []Item
+	for _, item := range items {
+		if favoritesOnly && !item.Annotation.Favorite {
+			continue
+		}
+		if anyTag || item.Annotation.HasTag(tag) {
+			tagged = append(tagged, item)
+		}
+	}
+	return tagged
+}
+
+func GetUniqueTags(items []Item) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, item := range items {
+		for _, tag := range item.Annotation.Tags {
+			if lower := strings.ToLower(tag); !seen[lower] {
+				seen[lower] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseTags(t *testing.T) {
+	assert.DeepEqual(t, ParseTags(" keep for  TR, sell,,Sell , guild loan"), []string{"keep for TR", "sell", "guild loan"})
+	assert.Equal(t, len(ParseTags(" , ")), 0)
+}
+
+func TestFilterTagged(t *testing.T) {
+	items := []Item{
+		{Name: "Ring", Annotation: Annotation{Tags: []string{"Sell"}}},
+		{Name: "Sword", Annotation: Annotation{Tags: []string{"keep for TR"}, Favorite: true}},
+		{Name: "Boots", Annotation: Annotation{Note: "guild loan from Bob"}},
+	}
+	names := func(items []Item) []string {
+		var names []string
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, len(FilterTagged(items, FilterAll, false)), 3)
+	assert.DeepEqual(t, names(FilterTagged(items, "sell", false)), []string{"Ring"})
+	assert.DeepEqual(t, names(FilterTagged(items, FilterAll, true)), []string{"Sword"})
+	assert.Equal(t, len(FilterTagged(items, "sell", true)), 0)
+	assert.DeepEqual(t, GetUniqueTags(append(items, Item{Annotation: Annotation{Tags: []string{"sell"}}})), []string{"Sell", "keep for TR"})
+
+	// Full text search also covers notes and tags.
+	assert.DeepEqual(t, names(FilterItems(items, FilterAll, FilterAll, FilterAll, "guild", 0, 40, FilterAll, FilterAll)), []string{"Boots"})
+	assert.DeepEqual(t, names(FilterItems(items, FilterAll, FilterAll, FilterAll, "keep for", 0, 40, FilterAll, FilterAll)), []string{"Sword"})
+}
@@ -0,0 +1,171 @@
+package db
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	MaxTroveFileBytes = 64 << 20
+	// MaxArchiveBytes caps the JSON read from one archive, uncompressed, and
+	// MaxArchiveFiles the number of JSON files in it, so that a small
+	// archive cannot exhaust memory.
+	MaxArchiveBytes = 256 << 20
+	MaxArchiveFiles = 1000
+	zipSuffix       = ".zip"
+	tarGzSuffix     = ".tar.gz"
+	tgzSuffix       = ".tgz"
+)
+
+var ErrArchiveTooLarge = errors.New("archive too large")
+
+// TroveFile is a Trove JSON file read from an archive or an upload.
+type TroveFile struct {
+	Name string
+	Data []byte
+}
+
+func IsArchive(filePath string) bool {
+	lower := strings.ToLower(filePath)
+	return strings.HasSuffix(lower, zipSuffix) || strings.HasSuffix(lower, tarGzSuffix) || strings.HasSuffix(lower, tgzSuffix)
+}
+
+// IsJSON tells whether a file name has the .json suffix, in any case.
+func IsJSON(filePath string) bool {
+	return strings.HasSuffix(strings.ToLower(filePath), jsonFileSuffix)
+}
+
+func IsZip(filePath string) bool {
+	return strings.HasSuffix(strings.ToLower(filePath), zipSuffix)
+}
+
+// ReadArchive returns the JSON files of a zip or gzipped tar archive. Only
+// base names are kept, so a zipped Trove folder reads like the folder itself.
+func ReadArchive(archivePath string) (files []TroveFile, err error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("open archive %q: %w", archivePath, err)
+	}
+	defer file.Close()
+
+	if !IsZip(archivePath) {
+		return ReadTarGz(file)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat archive %q: %w", archivePath, err)
+	}
+	return ReadZip(file, info.Size())
+}
+
+func ReadZip(reader io.ReaderAt, size int64) (files []TroveFile, err error) {
+	archive, err := zip.NewReader(reader, size)
+	if err != nil {
+		return nil, fmt.Errorf("open zip: %w", err)
+	}
+	budget := newArchiveBudget()
+	for _, entry := range archive.File {
+		name := path.Base(entry.Name)
+		if entry.FileInfo().IsDir() || !IsJSON(name) {
+			continue
+		}
+		data, readErr := readZipEntry(entry, budget)
+		if readErr != nil {
+			return nil, fmt.Errorf("read zip entry %q: %w", entry.Name, readErr)
+		}
+		files = append(files, TroveFile{Name: name, Data: data})
+	}
+	return files, nil
+}
+
+func readZipEntry(entry *zip.File, budget *archiveBudget) (data []byte, err error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer rc.Close()
+	return budget.read(rc)
+}
+
+func ReadTarGz(reader io.Reader) (files []TroveFile, err error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("open gzip: %w", err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	budget := newArchiveBudget()
+	for {
+		header, nextErr := tarReader.Next()
+		if errors.Is(nextErr, io.EOF) {
+			return files, nil
+		}
+		if nextErr != nil {
+			return nil, fmt.Errorf("read tar: %w", nextErr)
+		}
+		name := path.Base(header.Name)
+		if header.Typeflag != tar.TypeReg || !IsJSON(name) {
+			continue
+		}
+		data, readErr := budget.read(tarReader)
+		if readErr != nil {
+			return nil, fmt.Errorf("read tar entry %q: %w", header.Name, readErr)
+		}
+		files = append(files, TroveFile{Name: name, Data: data})
+	}
+}
+
+// archiveBudget tracks the files and bytes read from one archive so far.
+type archiveBudget struct {
+	maxFiles int
+	maxBytes int
+	files    int
+	bytes    int
+}
+
+func newArchiveBudget() *archiveBudget {
+	return &archiveBudget{maxFiles: MaxArchiveFiles, maxBytes: MaxArchiveBytes}
+}
+
+func (b *archiveBudget) read(reader io.Reader) (data []byte, err error) {
+	if b.files++; b.files > b.maxFiles {
+		return nil, fmt.Errorf("%w: more than %d JSON files", ErrArchiveTooLarge, b.maxFiles)
+	}
+	limit := min(MaxTroveFileBytes, b.maxBytes-b.bytes)
+	data, err = io.ReadAll(io.LimitReader(reader, int64(limit)+1))
+	if err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+	if len(data) > limit {
+		if limit < MaxTroveFileBytes {
+			return nil, fmt.Errorf("%w: more than %d bytes uncompressed", ErrArchiveTooLarge, b.maxBytes)
+		}
+		return nil, fmt.Errorf("file larger than %d bytes", MaxTroveFileBytes)
+	}
+	b.bytes += len(data)
+	return data, nil
+}
+
+func LoadItemsFromArchive(archivePath string) (allItems *AllItems, err error) {
+	files, err := ReadArchive(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	allItems = &AllItems{}
+	for _, file := range files {
+		if decodeErr := allItems.decode(file.Data); decodeErr != nil {
+			slog.Warn("failed to unmarshal archive entry as character/account data", "path", archivePath, "entry", file.Name, "err", decodeErr)
+		}
+	}
+	return allItems, nil
+}
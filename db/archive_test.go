@@ -0,0 +1,103 @@
+package db
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+var archiveEntries = map[string]string{
+	"Trove/character.json": `{"Name":"CharA","Inventory":[{"Name":"Sword","ItemType":"Weapon"}]}`,
+	"Trove/account.json":   `{"SharedBank":{"Tabs":{"0":{"Pages":{"0":{"Items":[{"Name":"Ring","ItemType":"Accessory"}]}}}}}}`,
+	"Trove/invalid.json":   `{"invalid": true`,
+	"Trove/readme.txt":     "noop",
+}
+
+func writeZipArchive(t *testing.T, path string) {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, content := range archiveEntries {
+		entry, err := writer.Create(name)
+		assert.NilError(t, err)
+		_, err = entry.Write([]byte(content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, writer.Close())
+	assert.NilError(t, os.WriteFile(path, buffer.Bytes(), 0o600))
+}
+
+func writeTarGzArchive(t *testing.T, path string) {
+	t.Helper()
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	assert.NilError(t, tarWriter.WriteHeader(&tar.Header{Name: "Trove/", Typeflag: tar.TypeDir, Mode: 0o755}))
+	for name, content := range archiveEntries {
+		assert.NilError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o600, Size: int64(len(content))}))
+		_, err := tarWriter.Write([]byte(content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tarWriter.Close())
+	assert.NilError(t, gzipWriter.Close())
+	assert.NilError(t, os.WriteFile(path, buffer.Bytes(), 0o600))
+}
+
+func TestLoadItemsFromArchive(t *testing.T) {
+	dir := t.TempDir()
+	testCases := []struct {
+		name  string
+		file  string
+		write func(t *testing.T, path string)
+	}{
+		{name: "zip", file: "trove.zip", write: writeZipArchive},
+		{name: "tar.gz", file: "trove.tar.gz", write: writeTarGzArchive},
+		{name: "tgz", file: "trove.TGZ", write: writeTarGzArchive},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			path := filepath.Join(dir, testCase.file)
+			testCase.write(t, path)
+			assert.Assert(t, IsArchive(path))
+
+			files, err := ReadArchive(path)
+			assert.NilError(t, err)
+			assert.Equal(t, len(files), 3)
+
+			allItems, err := LoadItemsFromArchive(path)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, GetUniqueCharacterNames(allItems.Items), []string{"Account (Shared Bank)", "CharA"})
+		})
+	}
+
+	assert.Assert(t, !IsArchive(filepath.Join(dir, "trove.json")))
+}
+
+func TestArchiveLimits(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for index := range MaxArchiveFiles + 1 {
+		entry, err := writer.Create(filepath.Join("Trove", strconv.Itoa(index)+".json"))
+		assert.NilError(t, err)
+		_, err = entry.Write([]byte("{}"))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, writer.Close())
+	_, err := ReadZip(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	assert.ErrorIs(t, err, ErrArchiveTooLarge)
+
+	budget := &archiveBudget{maxFiles: 3, maxBytes: 10}
+	_, err = budget.read(strings.NewReader("123456"))
+	assert.NilError(t, err)
+	_, err = budget.read(strings.NewReader("12345"))
+	assert.ErrorIs(t, err, ErrArchiveTooLarge)
+}
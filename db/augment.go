@@ -0,0 +1,165 @@
+package db
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	AugmentColorless  = "Colorless"
+	AugmentBlue       = "Blue"
+	AugmentRed        = "Red"
+	AugmentYellow     = "Yellow"
+	AugmentGreen      = "Green"
+	AugmentPurple     = "Purple"
+	AugmentOrange     = "Orange"
+	augmentItemType   = "Augment"
+	emptySlotPrefix   = "empty"
+	augmentWordLength = len("augment")
+)
+
+// slotAccepts lists the augment colors each slot color accepts. Multi-color
+// slots take either of their primary colors, and colorless augments fit any
+// of these slots. Colors not listed here (Moon, Sun, ...) only take their own.
+var slotAccepts = map[string][]string{
+	AugmentColorless: {AugmentColorless},
+	AugmentBlue:      {AugmentBlue, AugmentColorless},
+	AugmentRed:       {AugmentRed, AugmentColorless},
+	AugmentYellow:    {AugmentYellow, AugmentColorless},
+	AugmentGreen:     {AugmentGreen, AugmentBlue, AugmentYellow, AugmentColorless},
+	AugmentPurple:    {AugmentPurple, AugmentBlue, AugmentRed, AugmentColorless},
+	AugmentOrange:    {AugmentOrange, AugmentRed, AugmentYellow, AugmentColorless},
+}
+
+var augmentSlotColorPattern = regexp.MustCompile(`(?i)\b([a-z]+) augment slot`)
+
+type AugmentSlotPlan struct {
+	Index      int
+	Slot       AugmentSlot
+	Empty      bool
+	Candidates []Item
+}
+
+// IsEmpty reports whether no augment is slotted. Trove names empty slots
+// after the slot ("Empty Blue Augment Slot"), filled ones after the augment.
+func (s AugmentSlot) IsEmpty() bool {
+	name := strings.TrimSpace(strings.ToLower(s.Name))
+	return name == "" || strings.HasPrefix(name, emptySlotPrefix)
+}
+
+func SlotAccepts(slotColor, augmentColor string) bool {
+	slotColor = normalizeColor(slotColor)
+	augmentColor = normalizeColor(augmentColor)
+	accepted, known := slotAccepts[slotColor]
+	if !known {
+		return slotColor != "" && slotColor == augmentColor
+	}
+	for _, color := range accepted {
+		if color == augmentColor {
+			return true
+		}
+	}
+	return false
+}
+
+func IsAugment(item Item) bool {
+	return strings.EqualFold(item.ItemType, augmentItemType)
+}
+
+// AugmentColor works out the slot color an augment item goes into, first from
+// its sub type and then from the "... Augment Slot" wording in its text.
+func AugmentColor(item Item) string {
+	if !IsAugment(item) {
+		return ""
+	}
+	if color := knownColor(item.ItemSubType); color != "" {
+		return color
+	}
+	for _, text := range []string{item.Description, item.Name} {
+		if match := augmentSlotColorPattern.FindStringSubmatch(text); match != nil {
+			return normalizeColor(match[1])
+		}
+	}
+	if strings.Contains(strings.ToLower(item.Name), strings.ToLower(AugmentColorless)) {
+		return AugmentColorless
+	}
+	return ""
+}
+
+func knownColor(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) > augmentWordLength && strings.HasSuffix(strings.ToLower(value), "augment") {
+		value = strings.TrimSpace(value[:len(value)-augmentWordLength])
+	}
+	color := normalizeColor(value)
+	if _, known := slotAccepts[color]; known {
+		return color
+	}
+	return ""
+}
+
+func normalizeColor(color string) string {
+	color = strings.TrimSpace(color)
+	if color == "" {
+		return ""
+	}
+	return strings.ToUpper(color[:1]) + strings.ToLower(color[1:])
+}
+
+// GroupAugmentsByColor returns augment items keyed by slot color; augments
+// whose color cannot be determined are keyed by the empty string.
+func GroupAugmentsByColor(items []Item) map[string][]Item {
+	groups := make(map[string][]Item)
+	for _, item := range items {
+		if IsAugment(item) {
+			color := AugmentColor(item)
+			groups[color] = append(groups[color], item)
+		}
+	}
+	for color := range groups {
+		sortAugments(groups[color])
+	}
+	return groups
+}
+
+func HasOpenAugmentSlot(item Item) bool {
+	for _, slot := range item.AugmentSlots {
+		if slot.IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanAugmentSlots lists, for each augment slot of gear, the owned augments
+// that fit it. Augments above maxLevel are left out; maxLevel <= 0 disables
+// the limit. Filled slots are included without candidates.
+func PlanAugmentSlots(gear Item, items []Item, maxLevel int) []AugmentSlotPlan {
+	plans := make([]AugmentSlotPlan, 0, len(gear.AugmentSlots))
+	for index, slot := range gear.AugmentSlots {
+		plan := AugmentSlotPlan{Index: index, Slot: slot, Empty: slot.IsEmpty()}
+		if plan.Empty {
+			for _, item := range items {
+				if !IsAugment(item) || (maxLevel > 0 && item.MinimumLevel > maxLevel) {
+					continue
+				}
+				if SlotAccepts(slot.Color, AugmentColor(item)) {
+					plan.Candidates = append(plan.Candidates, item)
+				}
+			}
+			sortAugments(plan.Candidates)
+		}
+		plans = append(plans, plan)
+	}
+	return plans
+}
+
+func sortAugments(items []Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].MinimumLevel != items[j].MinimumLevel {
+			return items[i].MinimumLevel > items[j].MinimumLevel
+		}
+		return items[i].Name < items[j].Name
+	})
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSlotAccepts(t *testing.T) {
+	testCases := []struct {
+		slot    string
+		augment string
+		expect  bool
+	}{
+		{slot: "Blue", augment: "Blue", expect: true},
+		{slot: "Blue", augment: "Colorless", expect: true},
+		{slot: "Blue", augment: "Red", expect: false},
+		{slot: "Green", augment: "Blue", expect: true},
+		{slot: "Green", augment: "Yellow", expect: true},
+		{slot: "Green", augment: "Red", expect: false},
+		{slot: "Purple", augment: "Red", expect: true},
+		{slot: "Orange", augment: "yellow", expect: true},
+		{slot: "Colorless", augment: "Colorless", expect: true},
+		{slot: "Colorless", augment: "Blue", expect: false},
+		{slot: "Moon", augment: "Moon", expect: true},
+		{slot: "Moon", augment: "Colorless", expect: false},
+		{slot: "", augment: "", expect: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.slot+"/"+testCase.augment, func(t *testing.T) {
+			assert.Equal(t, SlotAccepts(testCase.slot, testCase.augment), testCase.expect)
+		})
+	}
+}
+
+func TestAugmentColor(t *testing.T) {
+	testCases := []struct {
+		name   string
+		item   Item
+		expect string
+	}{
+		{name: "sub type", item: Item{ItemType: "Augment", ItemSubType: "Blue Augment"}, expect: AugmentBlue},
+		{name: "description", item: Item{ItemType: "Augment", Description: "This augment fits in a Yellow Augment Slot."}, expect: AugmentYellow},
+		{name: "colorless name", item: Item{ItemType: "Augment", Name: "Colorless Crystal of Vigor"}, expect: AugmentColorless},
+		{name: "not an augment", item: Item{ItemType: "Weapon", Description: "Blue Augment Slot"}, expect: ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, AugmentColor(testCase.item), testCase.expect)
+		})
+	}
+}
+
+func TestPlanAugmentSlots(t *testing.T) {
+	gear := Item{
+		Name:         "Helm",
+		MinimumLevel: 20,
+		AugmentSlots: []AugmentSlot{
+			{Name: "Empty Green Augment Slot", Color: "Green"},
+			{Name: "Diamond of Wisdom +6", Color: "Yellow"},
+			{Name: "", Color: "Colorless"},
+		},
+	}
+	items := []Item{
+		gear,
+		{Name: "Sapphire of Vertigo +6", ItemType: "Augment", ItemSubType: "Blue", MinimumLevel: 10},
+		{Name: "Topaz of Greater Fire Spell Power", ItemType: "Augment", ItemSubType: "Yellow", MinimumLevel: 30},
+		{Name: "Ruby of Deadly +4", ItemType: "Augment", ItemSubType: "Red", MinimumLevel: 10},
+		{Name: "Crystal of Vigor", ItemType: "Augment", ItemSubType: "Colorless", MinimumLevel: 5},
+	}
+
+	assert.Assert(t, HasOpenAugmentSlot(gear))
+
+	plans := PlanAugmentSlots(gear, items, 0)
+	assert.Equal(t, len(plans), 3)
+	assert.Assert(t, plans[0].Empty)
+	assert.DeepEqual(t, itemNames(plans[0].Candidates), []string{"Topaz of Greater Fire Spell Power", "Sapphire of Vertigo +6", "Crystal of Vigor"})
+	assert.Assert(t, !plans[1].Empty)
+	assert.Equal(t, len(plans[1].Candidates), 0)
+	assert.DeepEqual(t, itemNames(plans[2].Candidates), []string{"Crystal of Vigor"})
+
+	limited := PlanAugmentSlots(gear, items, 20)
+	assert.DeepEqual(t, itemNames(limited[0].Candidates), []string{"Sapphire of Vertigo +6", "Crystal of Vigor"})
+
+	groups := GroupAugmentsByColor(items)
+	assert.Equal(t, len(groups[AugmentBlue]), 1)
+	assert.Equal(t, len(groups[AugmentColorless]), 1)
+}
+
+func itemNames(items []Item) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
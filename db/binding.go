@@ -0,0 +1,104 @@
+package db
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Binding is the bind state of an item, from least to most restrictive.
+type Binding int
+
+const (
+	BindingUnbound Binding = iota
+	BindingBoundToAccountOnEquip
+	BindingBoundToAccount
+	BindingBoundToCharacterOnEquip
+	BindingBoundToCharacter
+)
+
+var bindingNames = map[Binding]string{
+	BindingUnbound:                 "Unbound",
+	BindingBoundToAccountOnEquip:   "BoundToAccountOnEquip",
+	BindingBoundToAccount:          "BoundToAccount",
+	BindingBoundToCharacterOnEquip: "BoundToCharacterOnEquip",
+	BindingBoundToCharacter:        "BoundToCharacter",
+}
+
+// unknownBindings remembers the unrecognized values already logged.
+var unknownBindings sync.Map
+
+// bindingAliases maps normalized spellings, including the usual in-game
+// abbreviations, to bind states.
+var bindingAliases = map[string]Binding{
+	"":                        BindingUnbound,
+	"unbound":                 BindingUnbound,
+	"none":                    BindingUnbound,
+	"boundtoaccountonequip":   BindingBoundToAccountOnEquip,
+	"btaonequip":              BindingBoundToAccountOnEquip,
+	"btae":                    BindingBoundToAccountOnEquip,
+	"boundtoaccount":          BindingBoundToAccount,
+	"bta":                     BindingBoundToAccount,
+	"boundtocharacteronequip": BindingBoundToCharacterOnEquip,
+	"btconequip":              BindingBoundToCharacterOnEquip,
+	"btce":                    BindingBoundToCharacterOnEquip,
+	"boundtocharacter":        BindingBoundToCharacter,
+	"btc":                     BindingBoundToCharacter,
+}
+
+func (b Binding) String() string {
+	if name, known := bindingNames[b]; known {
+		return name
+	}
+	return "Unknown"
+}
+
+// Bound reports whether the item is already tied to an account or character,
+// as opposed to binding only once equipped.
+func (b Binding) Bound() bool {
+	return b == BindingBoundToAccount || b == BindingBoundToCharacter
+}
+
+// ParseBinding reads a Trove binding value or an abbreviation such as "BTA"
+// or "btc-on-equip"; known is false for values it does not recognize.
+func ParseBinding(value string) (binding Binding, known bool) {
+	normalized := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(value)))
+	binding, known = bindingAliases[normalized]
+	return binding, known
+}
+
+// BindingState returns the parsed Binding. Unrecognized values count as
+// bound to character, so that such items are never suggested for moving;
+// each such value is logged once.
+func (i Item) BindingState() Binding {
+	binding, known := ParseBinding(i.Binding)
+	if known {
+		return binding
+	}
+	if _, logged := unknownBindings.LoadOrStore(i.Binding, true); !logged {
+		slog.Warn("unknown binding, treating as bound to character", "binding", i.Binding)
+	}
+	return BindingBoundToCharacter
+}
+
+// BindingAllows reports whether the item's binding lets it move to the
+// holder with characterID (0 for an account bank) in the account alias, and
+// if not, why. Bound to character items stay with their owner; bound to
+// account items stay in their account, where an empty alias on either side
+// is unknown and allowed.
+func (i Item) BindingAllows(characterID int64, alias string) (allowed bool, reason string) {
+	switch i.BindingState() {
+	case BindingBoundToCharacter:
+		if characterID == 0 {
+			return false, "bound to character, cannot go to an account bank"
+		}
+		if characterID != i.OwnerID {
+			return false, "bound to another character"
+		}
+	case BindingBoundToAccount:
+		if i.SubscriptionAlias != "" && alias != "" && i.SubscriptionAlias != alias {
+			return false, "bound to another account"
+		}
+	}
+	return true, ""
+}
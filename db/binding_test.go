@@ -0,0 +1,69 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseBinding(t *testing.T) {
+	tests := []struct {
+		value   string
+		binding Binding
+		known   bool
+	}{
+		{value: "", binding: BindingUnbound, known: true},
+		{value: "BoundToAccount", binding: BindingBoundToAccount, known: true},
+		{value: "BoundToAccountOnEquip", binding: BindingBoundToAccountOnEquip, known: true},
+		{value: "BoundToCharacter", binding: BindingBoundToCharacter, known: true},
+		{value: "btc-on-equip", binding: BindingBoundToCharacterOnEquip, known: true},
+		{value: " BTA ", binding: BindingBoundToAccount, known: true},
+		{value: "Bound to Character", binding: BindingBoundToCharacter, known: true},
+		{value: "Soulbound", binding: BindingUnbound, known: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			binding, known := ParseBinding(tt.value)
+			assert.Equal(t, binding, tt.binding)
+			assert.Equal(t, known, tt.known)
+		})
+	}
+
+	assert.Equal(t, BindingBoundToCharacterOnEquip.String(), "BoundToCharacterOnEquip")
+	assert.Assert(t, BindingBoundToAccount.Bound())
+	assert.Assert(t, !BindingBoundToAccountOnEquip.Bound())
+	assert.Equal(t, Item{Binding: "Mystery"}.BindingState(), BindingBoundToCharacter)
+}
+
+func TestBindingAllows(t *testing.T) {
+	tests := []struct {
+		name        string
+		item        Item
+		characterID int64
+		alias       string
+		reason      string
+	}{
+		{name: "unbound", item: Item{}, characterID: 0, alias: "Alt"},
+		{name: "btc to owner", item: Item{Binding: "BTC", OwnerID: 1}, characterID: 1},
+		{name: "btc to bank", item: Item{Binding: "BTC", OwnerID: 1}, reason: "bound to character, cannot go to an account bank"},
+		{name: "btc to other", item: Item{Binding: "BTC", OwnerID: 1}, characterID: 2, reason: "bound to another character"},
+		{name: "bta same account", item: Item{Binding: "BTA", SubscriptionAlias: "Main"}, alias: "Main"},
+		{name: "bta other account", item: Item{Binding: "BTA", SubscriptionAlias: "Main"}, alias: "Alt", reason: "bound to another account"},
+		{name: "bta unknown item account", item: Item{Binding: "BTA"}, alias: "Alt"},
+		{name: "bta unknown holder account", item: Item{Binding: "BTA", SubscriptionAlias: "Main"}, characterID: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, reason := tt.item.BindingAllows(tt.characterID, tt.alias)
+			assert.Equal(t, allowed, tt.reason == "")
+			assert.Equal(t, reason, tt.reason)
+			// Transfer suggestions and the reorganization planner agree.
+			if tt.characterID != 0 {
+				character := CharacterInfo{CharacterID: tt.characterID, SubscriptionAlias: tt.alias}
+				assert.Equal(t, character.CanReceive(tt.item), allowed)
+			}
+			_, planned := resolveTarget(tt.item, MoveTarget{Holder: "Dest"}, []Holder{{Name: "Dest", CharacterID: tt.characterID, SubscriptionAlias: tt.alias}})
+			assert.Equal(t, planned, tt.reason)
+		})
+	}
+}
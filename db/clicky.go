@@ -0,0 +1,89 @@
+package db
+
+import (
+	"sort"
+	"strings"
+)
+
+// Clicky sort orders; the default groups spells by name.
+const (
+	ClickySortSpell       = "spell"
+	ClickySortCharges     = "charges"
+	ClickySortChargesDesc = "charges_desc"
+)
+
+type ClickySummary struct {
+	Spell string
+	Items []Item
+	// Charges and MaxCharges total the items that track charges, that is
+	// those with a MaxCharges.
+	Charges    int
+	MaxCharges int
+}
+
+// GroupItemsByClicky summarizes owned clickies per spell whose name contains
+// spell, case-insensitively. Sorting by charges puts the spells, and the
+// items within each spell, with the fewest charges left first, or the most
+// for ClickySortChargesDesc. Either way, items and spells without tracked
+// charges come last.
+func GroupItemsByClicky(items []Item, spell, sortBy string) []ClickySummary {
+	spellLower := strings.ToLower(spell)
+	bySpell := make(map[string]*ClickySummary)
+	var names []string
+	for _, item := range items {
+		if item.Clicky == nil || !strings.Contains(strings.ToLower(item.Clicky.SpellName), spellLower) {
+			continue
+		}
+		summary, exists := bySpell[item.Clicky.SpellName]
+		if !exists {
+			summary = &ClickySummary{Spell: item.Clicky.SpellName}
+			bySpell[item.Clicky.SpellName] = summary
+			names = append(names, item.Clicky.SpellName)
+		}
+		summary.Items = append(summary.Items, item)
+		if item.MaxCharges > 0 {
+			summary.Charges += item.Charges
+			summary.MaxCharges += item.MaxCharges
+		}
+	}
+	sort.Strings(names)
+
+	byCharges := sortBy == ClickySortCharges || sortBy == ClickySortChargesDesc
+	summaries := make([]ClickySummary, 0, len(names))
+	for _, name := range names {
+		summary := bySpell[name]
+		sort.SliceStable(summary.Items, func(i, j int) bool {
+			a, b := summary.Items[i], summary.Items[j]
+			if byCharges {
+				if less, decided := chargesLess(a.Charges, a.MaxCharges, b.Charges, b.MaxCharges, sortBy); decided {
+					return less
+				}
+			}
+			if a.CharacterName != b.CharacterName {
+				return a.CharacterName < b.CharacterName
+			}
+			return a.Name < b.Name
+		})
+		summaries = append(summaries, *summary)
+	}
+	if byCharges {
+		sort.SliceStable(summaries, func(i, j int) bool {
+			a, b := summaries[i], summaries[j]
+			less, _ := chargesLess(a.Charges, a.MaxCharges, b.Charges, b.MaxCharges, sortBy)
+			return less
+		})
+	}
+	return summaries
+}
+
+// chargesLess orders by charges left in the direction of sortBy, with
+// untracked charges (no MaxCharges) last. decided is false for ties.
+func chargesLess(aCharges, aMax, bCharges, bMax int, sortBy string) (less, decided bool) {
+	if (aMax > 0) != (bMax > 0) {
+		return aMax > 0, true
+	}
+	if aMax == 0 || aCharges == bCharges {
+		return false, false
+	}
+	return (aCharges < bCharges) == (sortBy == ClickySortCharges), true
+}
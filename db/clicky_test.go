@@ -0,0 +1,45 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGroupItemsByClicky(t *testing.T) {
+	raise := &Clicky{SpellName: "Raise Dead", CasterLevel: 9}
+	heal := &Clicky{SpellName: "Cure Light Wounds", CasterLevel: 1}
+	items := []Item{
+		{Name: "Wand of Raise Dead", CharacterName: "CharB", Clicky: raise, Charges: 2, MaxCharges: 10},
+		{Name: "Wand of Raise Dead", CharacterName: "CharA", Clicky: raise, Charges: 7, MaxCharges: 10},
+		{Name: "Healing Ring", CharacterName: "CharA", Clicky: heal, Charges: 20, MaxCharges: 25},
+		{Name: "Plain Boots", CharacterName: "CharA"},
+		{Name: "Everlasting Wand", CharacterName: "CharA", Clicky: raise, Charges: 1},
+		{Name: "Aura Goggles", CharacterName: "CharA", Clicky: &Clicky{SpellName: "See Invisibility"}},
+	}
+
+	clickies := GroupItemsByClicky(items, "", "")
+	assert.Equal(t, len(clickies), 3)
+	assert.Equal(t, clickies[0].Spell, "Cure Light Wounds")
+	raiseDead := clickies[1]
+	// The untracked Everlasting Wand does not count towards the totals.
+	assert.Equal(t, raiseDead.Charges, 9)
+	assert.Equal(t, raiseDead.MaxCharges, 20)
+	assert.Equal(t, raiseDead.Items[0].CharacterName, "CharA")
+
+	clickies = GroupItemsByClicky(items, "", ClickySortCharges)
+	assert.Equal(t, clickies[0].Spell, "Raise Dead")
+	assert.Equal(t, clickies[0].Items[0].CharacterName, "CharB")
+	assert.Equal(t, clickies[0].Items[2].Name, "Everlasting Wand")
+	assert.Equal(t, clickies[2].Spell, "See Invisibility")
+
+	clickies = GroupItemsByClicky(items, "", ClickySortChargesDesc)
+	assert.Equal(t, clickies[0].Spell, "Cure Light Wounds")
+	assert.Equal(t, clickies[1].Items[0].CharacterName, "CharA")
+	assert.Equal(t, clickies[1].Items[2].Name, "Everlasting Wand")
+	assert.Equal(t, clickies[2].Spell, "See Invisibility")
+
+	clickies = GroupItemsByClicky(items, "raise", "")
+	assert.Equal(t, len(clickies), 1)
+	assert.Equal(t, len(clickies[0].Items), 3)
+}
@@ -0,0 +1,150 @@
+package db
+
+import (
+	"sort"
+)
+
+// containerOrder puts the containers in the order the game shows them;
+// unknown containers sort after these, alphabetically.
+var containerOrder = map[string]int{
+	"Inventory":         0,
+	"Equipped":          1,
+	"PersonalBank":      2,
+	"ReincarnationBank": 3,
+	"SharedBank":        4,
+	"CraftingBank":      5,
+}
+
+type ContainerGrid struct {
+	Container string
+	Tab       int
+	TabName   string
+	Rows      int
+	Columns   int
+	// Cells is indexed [row][column], relative to the lowest row and column
+	// seen for this container across all holders.
+	Cells [][]*Item
+	// Unplaced holds items that share a cell with an earlier item.
+	Unplaced []Item
+}
+
+type gridBounds struct {
+	minRow, maxRow, minColumn, maxColumn int
+}
+
+type gridKey struct {
+	container string
+	tab       int
+}
+
+// containerCapacities maps containers of the named holder to their slot
+// count. A file's MaxCapacity covers the whole file, so it is only known per
+// container for holders with a single container besides Equipped.
+func containerCapacities(holders []Holder, name string) map[string]int {
+	capacities := make(map[string]int)
+	for _, holder := range holders {
+		if holder.Name != name || holder.MaxCapacity <= 0 {
+			continue
+		}
+		var containers []string
+		for _, container := range holder.Containers {
+			if container != "Equipped" {
+				containers = append(containers, container)
+			}
+		}
+		if len(containers) == 1 {
+			capacities[containers[0]] = holder.MaxCapacity
+		}
+	}
+	return capacities
+}
+
+// HolderContainers lays out the items of one holder (character or account
+// bank) as grids, one per container tab. Columns come from the column range
+// seen for the container among all items, so tabs of the same container
+// render alike even when mostly empty. When the holder's file reports a
+// MaxCapacity for its only container, the tabs get enough rows to show that
+// capacity split evenly across them; otherwise, as the files carry no tab
+// dimensions, rows fall back to the row range seen among all items.
+func HolderContainers(items []Item, holders []Holder, holder string) []ContainerGrid {
+	capacities := containerCapacities(holders, holder)
+	tabCounts := make(map[string]int)
+	seenTabs := make(map[gridKey]bool)
+	bounds := make(map[string]*gridBounds)
+	for _, item := range items {
+		key := gridKey{container: item.Container, tab: item.Tab}
+		if item.CharacterName == holder && !seenTabs[key] {
+			seenTabs[key] = true
+			tabCounts[item.Container]++
+		}
+		bound, exists := bounds[item.Container]
+		if !exists {
+			bounds[item.Container] = &gridBounds{minRow: item.Row, maxRow: item.Row, minColumn: item.Column, maxColumn: item.Column}
+			continue
+		}
+		bound.minRow = min(bound.minRow, item.Row)
+		bound.maxRow = max(bound.maxRow, item.Row)
+		bound.minColumn = min(bound.minColumn, item.Column)
+		bound.maxColumn = max(bound.maxColumn, item.Column)
+	}
+
+	grids := make(map[gridKey]*ContainerGrid)
+	for index := range items {
+		item := items[index]
+		if item.CharacterName != holder {
+			continue
+		}
+		key := gridKey{container: item.Container, tab: item.Tab}
+		grid, exists := grids[key]
+		if !exists {
+			bound := bounds[item.Container]
+			grid = &ContainerGrid{
+				Container: item.Container,
+				Tab:       item.Tab,
+				TabName:   item.TabName,
+				Rows:      bound.maxRow - bound.minRow + 1,
+				Columns:   bound.maxColumn - bound.minColumn + 1,
+			}
+			if capacity := capacities[item.Container]; capacity > 0 {
+				perTab := (capacity + tabCounts[item.Container] - 1) / tabCounts[item.Container]
+				grid.Rows = max(grid.Rows, (perTab+grid.Columns-1)/grid.Columns)
+			}
+			grid.Cells = make([][]*Item, grid.Rows)
+			for row := range grid.Cells {
+				grid.Cells[row] = make([]*Item, grid.Columns)
+			}
+			grids[key] = grid
+		}
+		if grid.TabName == "" {
+			grid.TabName = item.TabName
+		}
+
+		bound := bounds[item.Container]
+		cell := &grid.Cells[item.Row-bound.minRow][item.Column-bound.minColumn]
+		if *cell != nil {
+			grid.Unplaced = append(grid.Unplaced, item)
+			continue
+		}
+		*cell = &item
+	}
+
+	result := make([]ContainerGrid, 0, len(grids))
+	for _, grid := range grids {
+		result = append(result, *grid)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		rankI, knownI := containerOrder[result[i].Container]
+		rankJ, knownJ := containerOrder[result[j].Container]
+		if knownI != knownJ {
+			return knownI
+		}
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		if result[i].Container != result[j].Container {
+			return result[i].Container < result[j].Container
+		}
+		return result[i].Tab < result[j].Tab
+	})
+	return result
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestHolderContainers(t *testing.T) {
+	items := []Item{
+		{Name: "Potion", CharacterName: "CharA", Container: "Inventory", Tab: 1, TabName: "Bag 1", Row: 1, Column: 1},
+		{Name: "Sword", CharacterName: "CharA", Container: "Inventory", Tab: 1, Row: 2, Column: 3},
+		{Name: "Duplicate", CharacterName: "CharA", Container: "Inventory", Tab: 1, Row: 2, Column: 3},
+		{Name: "Shield", CharacterName: "CharB", Container: "Inventory", Tab: 1, Row: 4, Column: 4},
+		{Name: "Gem", CharacterName: "CharA", Container: "PersonalBank", Tab: 2, Row: 0, Column: 0},
+		{Name: "Odd", CharacterName: "CharA", Container: "Mystery", Tab: 0, Row: 0, Column: 0},
+	}
+
+	grids := HolderContainers(items, nil, "CharA")
+	assert.Equal(t, len(grids), 3)
+
+	inventory := grids[0]
+	assert.Equal(t, inventory.Container, "Inventory")
+	assert.Equal(t, inventory.TabName, "Bag 1")
+	// Bounds come from all holders, so CharB's shield widens the grid.
+	assert.Equal(t, inventory.Rows, 4)
+	assert.Equal(t, inventory.Columns, 4)
+	assert.Equal(t, inventory.Cells[0][0].Name, "Potion")
+	assert.Equal(t, inventory.Cells[1][2].Name, "Sword")
+	assert.Assert(t, inventory.Cells[3][3] == nil)
+	assert.Equal(t, len(inventory.Unplaced), 1)
+	assert.Equal(t, inventory.Unplaced[0].Name, "Duplicate")
+
+	assert.Equal(t, grids[1].Container, "PersonalBank")
+	assert.Equal(t, grids[2].Container, "Mystery")
+
+	assert.Equal(t, len(HolderContainers(items, nil, "Nobody")), 0)
+
+	// A known capacity adds the empty rows the items alone do not show.
+	holders := []Holder{
+		{Name: "CharA", Containers: []string{"Equipped", "Inventory"}, MaxCapacity: 40},
+		{Name: "CharA", Containers: []string{"Mystery", "PersonalBank"}, MaxCapacity: 100},
+	}
+	grids = HolderContainers(items, holders, "CharA")
+	assert.Equal(t, grids[0].Rows, 10)
+	assert.Equal(t, grids[0].Columns, 4)
+	assert.Equal(t, grids[0].Cells[1][2].Name, "Sword")
+	assert.Equal(t, grids[1].Rows, 1)
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FieldChange is one property of an item that differs between snapshots.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// ItemChange pairs the same item in two snapshots.
+type ItemChange struct {
+	Before  Item
+	After   Item
+	Changes []FieldChange
+}
+
+func (c ItemChange) Moved() bool {
+	return !sameSlot(c.Before, c.After)
+}
+
+// TroveDiff lists what happened between two snapshots. An item that moved
+// and changed appears in both Moved and Changed.
+type TroveDiff struct {
+	Gained  []Item
+	Lost    []Item
+	Moved   []ItemChange
+	Changed []ItemChange
+}
+
+// DiffItems matches items of two snapshots by identity. Copies sharing an
+// identity are paired with the copy in the same slot first, then in load
+// order. Filling an augment slot changes the identity of an item without an
+// ItemID, so such items left over are then paired by server, name and slot.
+// Items still left over were gained or lost.
+func DiffItems(before, after []Item) TroveDiff {
+	beforeIndex := NewItemIndex(before)
+	afterIndex := NewItemIndex(after)
+	var diff TroveDiff
+
+	identities := make([]string, 0, len(beforeIndex)+len(afterIndex))
+	for identity := range beforeIndex {
+		identities = append(identities, identity)
+	}
+	for identity := range afterIndex {
+		if _, exists := beforeIndex[identity]; !exists {
+			identities = append(identities, identity)
+		}
+	}
+	sort.Strings(identities)
+
+	var pairs []ItemChange
+	var lost, gained []Item
+	for _, identity := range identities {
+		newCopies := slices.Clone(afterIndex[identity])
+		var oldCopies []Item
+		for _, oldCopy := range beforeIndex[identity] {
+			match := slices.IndexFunc(newCopies, func(newCopy Item) bool { return sameSlot(oldCopy, newCopy) })
+			if match < 0 {
+				oldCopies = append(oldCopies, oldCopy)
+				continue
+			}
+			pairs = append(pairs, ItemChange{Before: oldCopy, After: newCopies[match]})
+			newCopies = slices.Delete(newCopies, match, match+1)
+		}
+		paired := min(len(oldCopies), len(newCopies))
+		for index := range paired {
+			pairs = append(pairs, ItemChange{Before: oldCopies[index], After: newCopies[index]})
+		}
+		lost = append(lost, oldCopies[paired:]...)
+		gained = append(gained, newCopies[paired:]...)
+	}
+
+	for _, oldCopy := range lost {
+		match := -1
+		if oldCopy.ItemID == 0 {
+			match = slices.IndexFunc(gained, func(newCopy Item) bool {
+				return newCopy.ItemID == 0 && newCopy.Server == oldCopy.Server && newCopy.Name == oldCopy.Name && sameSlot(oldCopy, newCopy)
+			})
+		}
+		if match < 0 {
+			diff.Lost = append(diff.Lost, oldCopy)
+			continue
+		}
+		pairs = append(pairs, ItemChange{Before: oldCopy, After: gained[match]})
+		gained = slices.Delete(gained, match, match+1)
+	}
+	diff.Gained = gained
+
+	for _, pair := range pairs {
+		pair.Changes = fieldChanges(pair.Before, pair.After)
+		if pair.Moved() {
+			diff.Moved = append(diff.Moved, pair)
+		}
+		if len(pair.Changes) > 0 {
+			diff.Changed = append(diff.Changed, pair)
+		}
+	}
+
+	byName := func(items []Item) {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	}
+	changesByName := func(changes []ItemChange) {
+		sort.SliceStable(changes, func(i, j int) bool { return changes[i].After.Name < changes[j].After.Name })
+	}
+	byName(diff.Gained)
+	byName(diff.Lost)
+	changesByName(diff.Moved)
+	changesByName(diff.Changed)
+	return diff
+}
+
+func sameSlot(a, b Item) bool {
+	return a.CharacterName == b.CharacterName && a.Container == b.Container && a.Tab == b.Tab && a.Row == b.Row && a.Column == b.Column
+}
+
+func fieldChanges(before, after Item) []FieldChange {
+	var changes []FieldChange
+	compare := func(field, beforeValue, afterValue string) {
+		if beforeValue != afterValue {
+			changes = append(changes, FieldChange{Field: field, Before: beforeValue, After: afterValue})
+		}
+	}
+	compare("quantity", strconv.Itoa(before.Quantity), strconv.Itoa(after.Quantity))
+	compare("charges", strconv.Itoa(before.Charges), strconv.Itoa(after.Charges))
+	compare("augments", augmentsText(before), augmentsText(after))
+	compare("binding", before.BindingState().String(), after.BindingState().String())
+	return changes
+}
+
+func augmentsText(item Item) string {
+	slots := make([]string, 0, len(item.AugmentSlots))
+	for _, slot := range item.AugmentSlots {
+		slots = append(slots, slot.Name)
+	}
+	return strings.Join(slots, ", ")
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDiffItems(t *testing.T) {
+	const server = "Argonnessen"
+	before := []Item{
+		{ItemID: 1, Name: "Ring", CharacterName: "CharA", Container: "Inventory", Row: 1, Quantity: 1},
+		{ItemID: 2, Name: "Wand", CharacterName: "CharA", Container: "Inventory", Row: 2, Quantity: 1, Charges: 10},
+		{ItemID: 3, Name: "Sword", CharacterName: "CharA", Container: "Inventory", Row: 3, Quantity: 1,
+			AugmentSlots: []AugmentSlot{{Name: "Empty Blue Augment Slot", Color: "Blue"}}},
+		{ItemID: 4, Name: "Junk", CharacterName: "CharA", Container: "Inventory", Row: 4, Quantity: 1},
+		{Name: "Potion", CharacterName: "CharA", Container: "Inventory", Row: 5, Quantity: 5},
+		{Name: "Potion", CharacterName: "CharB", Container: "Inventory", Row: 0, Quantity: 5},
+	}
+	after := []Item{
+		{ItemID: 1, Name: "Ring", CharacterName: accountSharedBankName, Container: "SharedBank", Tab: 1, Quantity: 1},
+		{ItemID: 2, Name: "Wand", CharacterName: "CharA", Container: "Inventory", Row: 2, Quantity: 1, Charges: 7},
+		{ItemID: 3, Name: "Sword", CharacterName: "CharB", Container: "Inventory", Row: 1, Quantity: 1,
+			AugmentSlots: []AugmentSlot{{Name: "Sapphire of Vertigo +5", Color: "Blue"}}},
+		{ItemID: 5, Name: "Tome", CharacterName: "CharA", Container: "Inventory", Row: 4, Quantity: 1},
+		// One potion stack stayed put with fewer potions, the other moved.
+		{Name: "Potion", CharacterName: "CharB", Container: "Inventory", Row: 0, Quantity: 3},
+		{Name: "Potion", CharacterName: "CharC", Container: "Inventory", Row: 0, Quantity: 5},
+	}
+	for index := range before {
+		before[index].Server = server
+	}
+	for index := range after {
+		after[index].Server = server
+	}
+
+	diff := DiffItems(before, after)
+	assert.Equal(t, len(diff.Gained), 1)
+	assert.Equal(t, diff.Gained[0].Name, "Tome")
+	assert.Equal(t, len(diff.Lost), 1)
+	assert.Equal(t, diff.Lost[0].Name, "Junk")
+
+	var moved []string
+	for _, change := range diff.Moved {
+		moved = append(moved, change.After.Name+" to "+change.After.CharacterName)
+	}
+	assert.DeepEqual(t, moved, []string{"Potion to CharC", "Ring to " + accountSharedBankName, "Sword to CharB"})
+
+	changes := make(map[string][]FieldChange)
+	for _, change := range diff.Changed {
+		changes[change.After.Name] = change.Changes
+	}
+	assert.DeepEqual(t, changes, map[string][]FieldChange{
+		"Potion": {{Field: "quantity", Before: "5", After: "3"}},
+		"Sword":  {{Field: "augments", Before: "Empty Blue Augment Slot", After: "Sapphire of Vertigo +5"}},
+		"Wand":   {{Field: "charges", Before: "10", After: "7"}},
+	})
+
+	diff = DiffItems(before, before)
+	assert.Equal(t, len(diff.Gained)+len(diff.Lost)+len(diff.Moved)+len(diff.Changed), 0)
+}
+
+func TestDiffItemsFillAugmentWithoutID(t *testing.T) {
+	empty := Item{Name: "Goggles", Server: "Argonnessen", CharacterName: "CharA", Container: "Inventory", Row: 1, Quantity: 1,
+		AugmentSlots: []AugmentSlot{{Name: "Empty Blue Augment Slot", Color: "Blue"}}}
+	filled := empty
+	filled.AugmentSlots = []AugmentSlot{{Name: "Sapphire of Vertigo +5", Color: "Blue"}}
+	assert.Assert(t, empty.Identity() != filled.Identity())
+
+	diff := DiffItems([]Item{empty}, []Item{filled})
+	assert.Equal(t, len(diff.Gained), 0)
+	assert.Equal(t, len(diff.Lost), 0)
+	assert.Equal(t, len(diff.Moved), 0)
+	assert.Equal(t, len(diff.Changed), 1)
+	assert.DeepEqual(t, diff.Changed[0].Changes, []FieldChange{{Field: "augments", Before: "Empty Blue Augment Slot", After: "Sapphire of Vertigo +5"}})
+
+	// A fingerprinted item that also moved is not the same item.
+	moved := filled
+	moved.Row = 2
+	diff = DiffItems([]Item{empty}, []Item{moved})
+	assert.Equal(t, len(diff.Gained), 1)
+	assert.Equal(t, len(diff.Lost), 1)
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+const fingerprintPrefix = "fp-"
+
+// Identity is a key for item that stays the same when the item moves
+// between containers, characters and banks, so that notes and history can
+// follow it across reloads.
+//
+// Items with an ItemID use "<server>/<ItemID>"; Trove item IDs are unique
+// per server. Items without one fall back to "<server>/fp-<hash>", a
+// fingerprint of the name, effects and augment slots. Identical copies then
+// share an identity, and filling an augment slot changes it.
+func (item Item) Identity() string {
+	if item.ItemID != 0 {
+		return item.Server + "/" + strconv.FormatInt(item.ItemID, 10)
+	}
+	return item.Server + "/" + fingerprintPrefix + Fingerprint(item)
+}
+
+// Fingerprint hashes what describes an item independently of where it is.
+func Fingerprint(item Item) string {
+	var builder strings.Builder
+	field := func(value string) {
+		// Length prefixes keep adjacent fields from running into each other.
+		builder.WriteString(strconv.Itoa(len(value)))
+		builder.WriteByte(':')
+		builder.WriteString(value)
+	}
+	field(item.Name)
+	for _, effect := range item.Effects {
+		field(effect.Name)
+		field(effect.Description)
+	}
+	builder.WriteByte('|')
+	for _, slot := range item.AugmentSlots {
+		field(slot.Name)
+		field(slot.Color)
+	}
+	sum := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ItemIndex maps identities to items. Fingerprinted copies share an
+// identity, so one identity may list several items.
+type ItemIndex map[string][]Item
+
+func NewItemIndex(items []Item) ItemIndex {
+	index := make(ItemIndex, len(items))
+	for _, item := range items {
+		identity := item.Identity()
+		index[identity] = append(index[identity], item)
+	}
+	return index
+}
+
+// Find returns the first item loaded with identity.
+func (index ItemIndex) Find(identity string) (item Item, found bool) {
+	items := index[identity]
+	if len(items) == 0 {
+		return item, false
+	}
+	return items[0], true
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestItemIdentity(t *testing.T) {
+	// The same two items before and after moving from CharA's inventory to
+	// the shared bank and CharB's bank.
+	before, err := DecodeItems([]byte(`{
+		"CharacterId": 1, "Name": "CharA", "Server": "Argonnessen",
+		"Inventory": [
+			{"ItemId": 42, "OwnerId": 1, "Name": "Ring", "Container": "Inventory", "Row": 1, "Column": 2},
+			{"Name": "Topaz", "Container": "Inventory", "Effects": [{"Name": "Resistance +1"}], "AugmentSlots": [{"Name": "Empty", "Color": "Yellow"}]}
+		]
+	}`))
+	assert.NilError(t, err)
+	shared, err := DecodeItems([]byte(`{
+		"Server": "Argonnessen",
+		"SharedBank": {"Tabs": {"0": {"Pages": {"0": {"Items": [
+			{"ItemId": 42, "OwnerId": 1, "Name": "Ring", "Container": "SharedBank", "Tab": 2, "Row": 0, "Column": 0}
+		]}}}}}
+	}`))
+	assert.NilError(t, err)
+	bank, err := DecodeItems([]byte(`{
+		"CharacterId": 2, "Name": "CharB", "Server": "Argonnessen",
+		"PersonalBank": {"Tabs": {"0": {"Pages": {"0": {"Items": [
+			{"Name": "Topaz", "Container": "PersonalBank", "Tab": 1, "Effects": [{"Name": "Resistance +1"}], "AugmentSlots": [{"Name": "Empty", "Color": "Yellow"}]}
+		]}}}}}
+	}`))
+	assert.NilError(t, err)
+
+	assert.Equal(t, before[0].Identity(), "Argonnessen/42")
+	assert.Equal(t, shared[0].Identity(), before[0].Identity())
+	assert.Equal(t, bank[0].Identity(), before[1].Identity())
+	assert.Assert(t, before[1].Identity() != before[0].Identity())
+
+	tests := []struct {
+		name   string
+		change func(*Item)
+	}{
+		{name: "other server", change: func(item *Item) { item.Server = "Thelanis" }},
+		{name: "other effect", change: func(item *Item) { item.Effects[0].Name = "Resistance +2" }},
+		{name: "filled augment", change: func(item *Item) { item.AugmentSlots[0].Name = "Topaz of Resistance" }},
+		{name: "fields do not run together", change: func(item *Item) {
+			item.Name, item.Effects[0].Name = "TopazResistance", " +1"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := bank[0]
+			changed.Effects = append([]Effect(nil), changed.Effects...)
+			changed.AugmentSlots = append([]AugmentSlot(nil), changed.AugmentSlots...)
+			tt.change(&changed)
+			assert.Assert(t, changed.Identity() != bank[0].Identity())
+		})
+	}
+}
+
+func TestItemIndex(t *testing.T) {
+	copyA := Item{Name: "Potion", CharacterName: "CharA"}
+	copyB := Item{Name: "Potion", CharacterName: "CharB"}
+	ring := Item{ItemID: 42, Name: "Ring", Server: "Argonnessen", CharacterName: "CharA"}
+	index := NewItemIndex([]Item{copyA, ring, copyB})
+
+	found, ok := index.Find("Argonnessen/42")
+	assert.Assert(t, ok)
+	assert.Equal(t, found.Name, "Ring")
+
+	found, ok = index.Find(copyB.Identity())
+	assert.Assert(t, ok)
+	assert.Equal(t, found.CharacterName, "CharA")
+	assert.Equal(t, len(index[copyB.Identity()]), 2)
+
+	_, ok = index.Find("Argonnessen/43")
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const craftingBankContainer = "CraftingBank"
+
+var ingredientItemTypes = map[string]bool{
+	"Ingredient":  true,
+	"Collectible": true,
+}
+
+// IsIngredient tells crafting ingredients and collectibles apart from gear.
+func IsIngredient(item Item) bool {
+	return ingredientItemTypes[item.ItemType] || item.Container == craftingBankContainer
+}
+
+type IngredientHolding struct {
+	Holder   string
+	Quantity int
+}
+
+// IngredientTotal sums the stacks of one ingredient across all holders.
+type IngredientTotal struct {
+	Name     string
+	WeenieID int64
+	Quantity int
+	Holders  []IngredientHolding
+	// Target is the amount wanted, zero when none was asked for.
+	Target int
+}
+
+func (t IngredientTotal) Missing() int {
+	return max(t.Target-t.Quantity, 0)
+}
+
+type IngredientTarget struct {
+	Name     string `json:"name"     yaml:"name"`
+	Quantity int    `json:"quantity" yaml:"quantity"`
+}
+
+// AggregateIngredients sums ingredient quantities per name, sorted by name,
+// with holders sorted by name.
+func AggregateIngredients(items []Item) []IngredientTotal {
+	byName := make(map[string]*IngredientTotal)
+	perHolder := make(map[string]map[string]int)
+	var names []string
+	for _, item := range items {
+		if !IsIngredient(item) {
+			continue
+		}
+		total, exists := byName[item.Name]
+		if !exists {
+			total = &IngredientTotal{Name: item.Name, WeenieID: item.WeenieID}
+			byName[item.Name] = total
+			perHolder[item.Name] = make(map[string]int)
+			names = append(names, item.Name)
+		}
+		quantity := max(item.Quantity, 1)
+		total.Quantity += quantity
+		perHolder[item.Name][item.CharacterName] += quantity
+	}
+	sort.Strings(names)
+
+	totals := make([]IngredientTotal, 0, len(names))
+	for _, name := range names {
+		total := byName[name]
+		for holder, quantity := range perHolder[name] {
+			total.Holders = append(total.Holders, IngredientHolding{Holder: holder, Quantity: quantity})
+		}
+		sort.Slice(total.Holders, func(i, j int) bool { return total.Holders[i].Holder < total.Holders[j].Holder })
+		totals = append(totals, *total)
+	}
+	return totals
+}
+
+// ApplyTargets returns the totals of the targeted ingredients in target
+// order, matching names case-insensitively. Targets nobody owns get a zero
+// quantity; repeated names add up.
+func ApplyTargets(totals []IngredientTotal, targets []IngredientTarget) []IngredientTotal {
+	byName := make(map[string]IngredientTotal, len(totals))
+	for _, total := range totals {
+		byName[strings.ToLower(total.Name)] = total
+	}
+	var result []IngredientTotal
+	index := make(map[string]int)
+	for _, target := range targets {
+		key := strings.ToLower(target.Name)
+		if position, exists := index[key]; exists {
+			result[position].Target += target.Quantity
+			continue
+		}
+		total, exists := byName[key]
+		if !exists {
+			total = IngredientTotal{Name: target.Name}
+		}
+		total.Target = target.Quantity
+		index[key] = len(result)
+		result = append(result, total)
+	}
+	return result
+}
+
+// ParseIngredientTargets reads one "Name: quantity" target per line,
+// skipping blank lines and "#" comments.
+func ParseIngredientTargets(text string) ([]IngredientTarget, error) {
+	var targets []IngredientTarget
+	for number, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		separator := strings.LastIndex(line, ":")
+		if separator < 0 {
+			return nil, fmt.Errorf("line %d: expected \"Name: quantity\"", number+1)
+		}
+		name := strings.TrimSpace(line[:separator])
+		quantity, err := strconv.Atoi(strings.TrimSpace(line[separator+1:]))
+		if err != nil || quantity <= 0 || name == "" {
+			return nil, fmt.Errorf("line %d: expected \"Name: quantity\" with a positive quantity", number+1)
+		}
+		targets = append(targets, IngredientTarget{Name: name, Quantity: quantity})
+	}
+	return targets, nil
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestAggregateIngredients(t *testing.T) {
+	items := []Item{
+		{Name: "Cannith Essence", ItemType: "Ingredient", WeenieID: 7, CharacterName: "CharB", Quantity: 50},
+		{Name: "Cannith Essence", ItemType: "Ingredient", WeenieID: 7, CharacterName: "CharA", Quantity: 20},
+		{Name: "Cannith Essence", ItemType: "Ingredient", WeenieID: 7, CharacterName: "CharA", Quantity: 5},
+		{Name: "Shroud Gem", CharacterName: "Account (Crafting Bank)", Container: "CraftingBank", Quantity: 3},
+		{Name: "Bone Fragment", ItemType: "Collectible", CharacterName: "CharA"},
+		{Name: "Flaming Sword", ItemType: "Weapon", CharacterName: "CharA", Quantity: 1},
+	}
+
+	totals := AggregateIngredients(items)
+	assert.Equal(t, len(totals), 3)
+	assert.Equal(t, totals[0].Name, "Bone Fragment")
+	assert.Equal(t, totals[0].Quantity, 1)
+	essence := totals[1]
+	assert.Equal(t, essence.Quantity, 75)
+	assert.Equal(t, essence.WeenieID, int64(7))
+	assert.DeepEqual(t, essence.Holders, []IngredientHolding{{Holder: "CharA", Quantity: 25}, {Holder: "CharB", Quantity: 50}})
+
+	targeted := ApplyTargets(totals, []IngredientTarget{
+		{Name: "shroud gem", Quantity: 5},
+		{Name: "Cannith Essence", Quantity: 60},
+		{Name: "Dragon Scale", Quantity: 1},
+		{Name: "Shroud Gem", Quantity: 1},
+	})
+	assert.Equal(t, len(targeted), 3)
+	assert.Equal(t, targeted[0].Name, "Shroud Gem")
+	assert.Equal(t, targeted[0].Target, 6)
+	assert.Equal(t, targeted[0].Missing(), 3)
+	assert.Equal(t, targeted[1].Missing(), 0)
+	assert.Equal(t, targeted[2].Quantity, 0)
+	assert.Equal(t, targeted[2].Missing(), 1)
+}
+
+func TestParseIngredientTargets(t *testing.T) {
+	targets, err := ParseIngredientTargets("# name: quantity\nCannith Essence: 60\n\n  Shroud: Gem : 2 \n")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, targets, []IngredientTarget{{Name: "Cannith Essence", Quantity: 60}, {Name: "Shroud: Gem", Quantity: 2}})
+
+	_, err = ParseIngredientTargets("Cannith Essence 60")
+	assert.ErrorContains(t, err, "line 1")
+	_, err = ParseIngredientTargets("ok: 1\nCannith Essence: -1")
+	assert.ErrorContains(t, err, "line 2")
+	_, err = ParseIngredientTargets("# comment\nCannith Essence 60")
+	assert.ErrorContains(t, err, "line 2")
+}
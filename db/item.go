@@ -2,6 +2,7 @@ package db
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -13,12 +14,14 @@ import (
 
 const (
 	FilterAll               = "All"
-	BindingBoundToCharacter = "BoundToCharacter"
 	jsonFileSuffix          = ".json"
 	accountSharedBankName   = "Account (Shared Bank)"
 	accountCraftingBankName = "Account (Crafting Bank)"
 )
 
+// ErrUnknownPayload is returned when JSON data is neither character nor account data.
+var ErrUnknownPayload = errors.New("not Trove character or account data")
+
 type CharacterData struct {
 	CharacterID         int64      `json:"CharacterId"`
 	Name                string     `json:"Name"`
@@ -94,6 +97,14 @@ type Item struct {
 	SetBonus1Name        string        `json:"SetBonus1Name,omitempty"`
 	SetBonus1Description []string      `json:"SetBonus1Description,omitempty"`
 	MinorArtifact        bool          `json:"MinorArtifact,omitempty"`
+
+	// Filled in by the loader from the enclosing file rather than the item JSON.
+	Server            string `json:"Server,omitempty"`
+	SubscriptionAlias string `json:"SubscriptionAlias,omitempty"`
+	SourcePath        string `json:"-"`
+
+	// Attached from local state by the application.
+	Annotation Annotation `json:"-"`
 }
 
 type Clicky struct {
@@ -114,7 +125,21 @@ type Effect struct {
 }
 
 type AllItems struct {
-	Items []Item
+	Items   []Item
+	Holders []Holder
+}
+
+// Holder is the owner of one Trove file: a character's inventory or bank, or
+// an account bank. A character with both files has two holders of the same
+// name, told apart by Containers.
+type Holder struct {
+	Name              string
+	CharacterID       int64
+	Server            string
+	SubscriptionAlias string
+	Containers        []string
+	UsedCapacity      int
+	MaxCapacity       int
 }
 
 func LoadItemsFromDir(dirPath string) (allItems *AllItems, err error) {
@@ -126,7 +151,7 @@ func LoadItemsFromDir(dirPath string) (allItems *AllItems, err error) {
 	}
 
 	for _, file := range files {
-		if file.IsDir() || !strings.HasSuffix(file.Name(), jsonFileSuffix) {
+		if file.IsDir() || !IsJSON(file.Name()) {
 			continue
 		}
 
@@ -137,29 +162,81 @@ func LoadItemsFromDir(dirPath string) (allItems *AllItems, err error) {
 			continue
 		}
 
-		var charData CharacterData
-		if unmarshalErr := json.Unmarshal(data, &charData); unmarshalErr == nil {
-			if hasCharacterPayload(charData) {
-				appendItemsFromBank(&allItems.Items, charData.PersonalBank, charData.Name)
-				appendItemsFromBank(&allItems.Items, charData.ReincarnationBank, charData.Name)
-				appendItemsWithCharacter(&allItems.Items, charData.Inventory, charData.Name)
-				continue
-			}
+		if decodeErr := allItems.decode(data); decodeErr != nil {
+			slog.Warn("failed to unmarshal file as character/account data", "path", filePath, "err", decodeErr)
 		}
+	}
 
-		var accountData AccountData
-		if unmarshalErr := json.Unmarshal(data, &accountData); unmarshalErr == nil {
-			if hasAccountPayload(accountData) {
-				appendItemsFromBank(&allItems.Items, accountData.SharedBank, accountSharedBankName)
-				appendItemsFromBank(&allItems.Items, accountData.CraftingBank, accountCraftingBankName)
-				continue
-			}
+	return allItems, nil
+}
+
+// DecodeItems decodes one Trove JSON file holding either character or account data.
+func DecodeItems(data []byte) (items []Item, err error) {
+	decoded := &AllItems{}
+	if err = decoded.decode(data); err != nil {
+		return nil, err
+	}
+	return decoded.Items, nil
+}
+
+func (a *AllItems) decode(data []byte) error {
+	var charData CharacterData
+	if unmarshalErr := json.Unmarshal(data, &charData); unmarshalErr == nil {
+		if hasCharacterPayload(charData) {
+			origin := itemOrigin{characterName: charData.Name, server: charData.Server, subscriptionAlias: charData.SubscriptionAlias}
+			start := len(a.Items)
+			appendItemsFromBank(&a.Items, charData.PersonalBank, origin)
+			appendItemsFromBank(&a.Items, charData.ReincarnationBank, origin)
+			appendItemsWithOrigin(&a.Items, charData.Inventory, origin)
+			holder := newHolder(origin, a.Items[start:], charData.UsedCapacity, charData.MaxCapacity)
+			holder.CharacterID = charData.CharacterID
+			a.Holders = append(a.Holders, holder)
+			return nil
 		}
+	}
 
-		slog.Warn("failed to unmarshal file as character/account data", "path", filePath)
+	var accountData AccountData
+	if unmarshalErr := json.Unmarshal(data, &accountData); unmarshalErr == nil {
+		if hasAccountPayload(accountData) {
+			origin := itemOrigin{characterName: accountSharedBankName, server: accountData.Server, subscriptionAlias: accountData.SubscriptionAlias}
+			start := len(a.Items)
+			appendItemsFromBank(&a.Items, accountData.SharedBank, origin)
+			if accountData.SharedBank != nil {
+				a.Holders = append(a.Holders, newHolder(origin, a.Items[start:], accountData.UsedCapacity, accountData.MaxCapacity))
+			}
+
+			origin.characterName = accountCraftingBankName
+			start = len(a.Items)
+			appendItemsFromBank(&a.Items, accountData.CraftingBank, origin)
+			if accountData.CraftingBank != nil {
+				a.Holders = append(a.Holders, newHolder(origin, a.Items[start:], 0, 0))
+			}
+			return nil
+		}
 	}
 
-	return allItems, nil
+	return ErrUnknownPayload
+}
+
+func newHolder(origin itemOrigin, items []Item, usedCapacity, maxCapacity int) Holder {
+	holder := Holder{
+		Name:         origin.characterName,
+		Server:       origin.server,
+		UsedCapacity: usedCapacity,
+		MaxCapacity:  maxCapacity,
+	}
+	if origin.subscriptionAlias != nil {
+		holder.SubscriptionAlias = *origin.subscriptionAlias
+	}
+	seen := make(map[string]bool)
+	for _, item := range items {
+		if item.Container != "" && !seen[item.Container] {
+			seen[item.Container] = true
+			holder.Containers = append(holder.Containers, item.Container)
+		}
+	}
+	sort.Strings(holder.Containers)
+	return holder
 }
 
 func hasCharacterPayload(value CharacterData) bool {
@@ -170,30 +247,40 @@ func hasAccountPayload(value AccountData) bool {
 	return value.SharedBank != nil || value.CraftingBank != nil
 }
 
-func appendItemsFromBank(dst *[]Item, bank *Bank, characterName string) {
+type itemOrigin struct {
+	characterName     string
+	server            string
+	subscriptionAlias *string
+}
+
+func appendItemsFromBank(dst *[]Item, bank *Bank, origin itemOrigin) {
 	if bank == nil {
 		return
 	}
 	for _, tab := range bank.Tabs {
 		for _, page := range tab.Pages {
-			appendItemsWithCharacter(dst, page.Items, characterName)
+			appendItemsWithOrigin(dst, page.Items, origin)
 		}
 	}
 }
 
-func appendItemsWithCharacter(dst *[]Item, source []Item, characterName string) {
+func appendItemsWithOrigin(dst *[]Item, source []Item, origin itemOrigin) {
 	if len(source) == 0 {
 		return
 	}
 	items := make([]Item, len(source))
 	copy(items, source)
 	for index := range items {
-		items[index].CharacterName = characterName
+		items[index].CharacterName = origin.characterName
+		items[index].Server = origin.server
+		if origin.subscriptionAlias != nil {
+			items[index].SubscriptionAlias = *origin.subscriptionAlias
+		}
 	}
 	*dst = append(*dst, items...)
 }
 
-func FilterItems(items []Item, itemType, itemSubType, characterName, nameSearch string, minLevel, maxLevel int, equipsTo string) []Item {
+func FilterItems(items []Item, itemType, itemSubType, characterName, nameSearch string, minLevel, maxLevel int, equipsTo, setName string) []Item {
 	var nameMatches []Item
 	var effectMatches []Item
 
@@ -204,6 +291,7 @@ func FilterItems(items []Item, itemType, itemSubType, characterName, nameSearch
 		matchItemSubType := itemSubType == "" || itemSubType == FilterAll || item.ItemSubType == itemSubType
 		matchCharacterName := characterName == "" || characterName == FilterAll || item.CharacterName == characterName
 		matchMinLevel := item.MinimumLevel >= minLevel && item.MinimumLevel <= maxLevel
+		matchSetName := setName == "" || setName == FilterAll || item.SetBonus1Name == setName
 
 		matchEquipsTo := equipsTo == "" || equipsTo == FilterAll
 		if !matchEquipsTo {
@@ -215,7 +303,7 @@ func FilterItems(items []Item, itemType, itemSubType, characterName, nameSearch
 			}
 		}
 
-		if !matchItemType || !matchItemSubType || !matchCharacterName || !matchMinLevel || !matchEquipsTo {
+		if !matchItemType || !matchItemSubType || !matchCharacterName || !matchMinLevel || !matchEquipsTo || !matchSetName {
 			continue
 		}
 
@@ -241,9 +329,25 @@ func FilterItems(items []Item, itemType, itemSubType, characterName, nameSearch
 				strings.Contains(strings.ToLower(item.Clicky.SpellDescription), searchLower)
 		}
 
+		setMatch := strings.Contains(strings.ToLower(item.SetBonus1Name), searchLower)
+		for _, description := range item.SetBonus1Description {
+			if setMatch {
+				break
+			}
+			setMatch = strings.Contains(strings.ToLower(description), searchLower)
+		}
+
+		noteMatch := strings.Contains(strings.ToLower(item.Annotation.Note), searchLower)
+		for _, tag := range item.Annotation.Tags {
+			if noteMatch {
+				break
+			}
+			noteMatch = strings.Contains(strings.ToLower(tag), searchLower)
+		}
+
 		if nameMatch {
 			nameMatches = append(nameMatches, item)
-		} else if effectMatch || descMatch || clickyMatch {
+		} else if effectMatch || descMatch || clickyMatch || setMatch || noteMatch {
 			effectMatches = append(effectMatches, item)
 		}
 	}
@@ -12,9 +12,14 @@ func TestLoadItemsFromDir(t *testing.T) {
 	dir := t.TempDir()
 
 	charJSON := `{
+		"CharacterId": 42,
 		"Name": "CharA",
+		"UsedCapacity": 1,
+		"MaxCapacity": 80,
+		"Server": "Ghallanda",
+		"SubscriptionAlias": "Main",
 		"Inventory": [
-			{"Name":"Sword","ItemType":"Weapon","ItemSubType":"Sword","MinimumLevel":1,"EquipsTo":["Hand"]}
+			{"Name":"Sword","Container":"Inventory","ItemType":"Weapon","ItemSubType":"Sword","MinimumLevel":1,"EquipsTo":["Hand"]}
 		]
 	}`
 	accountJSON := `{
@@ -47,6 +52,13 @@ func TestLoadItemsFromDir(t *testing.T) {
 	assert.Equal(t, allItems.Items[0].CharacterName, "Account (Shared Bank)")
 	assert.Equal(t, allItems.Items[1].Name, "Sword")
 	assert.Equal(t, allItems.Items[1].CharacterName, "CharA")
+	assert.Equal(t, allItems.Items[1].Server, "Ghallanda")
+	assert.Equal(t, allItems.Items[1].SubscriptionAlias, "Main")
+
+	assert.DeepEqual(t, allItems.Holders, []Holder{
+		{Name: "Account (Shared Bank)"},
+		{Name: "CharA", CharacterID: 42, Server: "Ghallanda", SubscriptionAlias: "Main", Containers: []string{"Inventory"}, UsedCapacity: 1, MaxCapacity: 80},
+	})
 }
 
 func TestFilterItems(t *testing.T) {
@@ -72,15 +84,17 @@ func TestFilterItems(t *testing.T) {
 			Effects:       []Effect{{Name: "Cold Resist", Description: "Resists cold"}},
 		},
 		{
-			Name:          "Arcane Cloak",
-			ItemType:      "Armor",
-			ItemSubType:   "Cloak",
-			CharacterName: "CharA",
-			MinimumLevel:  8,
-			Description:   "Spell focus",
-			EquipsTo:      []string{"Back"},
-			Effects:       []Effect{{Name: "Spell Power", Description: "Arcane bonus"}},
-			Clicky:        &Clicky{SpellName: "Teleport", SpellDescription: "Travel quickly"},
+			Name:                 "Arcane Cloak",
+			ItemType:             "Armor",
+			ItemSubType:          "Cloak",
+			CharacterName:        "CharA",
+			MinimumLevel:         8,
+			Description:          "Spell focus",
+			EquipsTo:             []string{"Back"},
+			Effects:              []Effect{{Name: "Spell Power", Description: "Arcane bonus"}},
+			Clicky:               &Clicky{SpellName: "Teleport", SpellDescription: "Travel quickly"},
+			SetBonus1Name:        "Wayfarer",
+			SetBonus1Description: []string{"2 Pieces Equipped: +1 Striding"},
 		},
 	}
 
@@ -93,6 +107,7 @@ func TestFilterItems(t *testing.T) {
 		minLevel     int
 		maxLevel     int
 		equipsTo     string
+		setName      string
 		expectedSize int
 		firstName    string
 	}{
@@ -144,6 +159,31 @@ func TestFilterItems(t *testing.T) {
 			expectedSize: 1,
 			firstName:    "Arcane Cloak",
 		},
+		{
+			name:         "set name",
+			itemType:     FilterAll,
+			itemSubType:  FilterAll,
+			character:    FilterAll,
+			nameSearch:   "",
+			minLevel:     0,
+			maxLevel:     40,
+			equipsTo:     FilterAll,
+			setName:      "Wayfarer",
+			expectedSize: 1,
+			firstName:    "Arcane Cloak",
+		},
+		{
+			name:         "full text matches set bonus",
+			itemType:     FilterAll,
+			itemSubType:  FilterAll,
+			character:    FilterAll,
+			nameSearch:   "striding",
+			minLevel:     0,
+			maxLevel:     40,
+			equipsTo:     FilterAll,
+			expectedSize: 1,
+			firstName:    "Arcane Cloak",
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -157,6 +197,7 @@ func TestFilterItems(t *testing.T) {
 				testCase.minLevel,
 				testCase.maxLevel,
 				testCase.equipsTo,
+				testCase.setName,
 			)
 
 			assert.Equal(t, len(result), testCase.expectedSize)
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Coin values in copper; each coin is worth ten of the next smaller one.
+const (
+	CopperPerSilver   = 10
+	CopperPerGold     = 100
+	CopperPerPlatinum = 1000
+)
+
+const (
+	junkReasonLowLevel  = "low level"
+	junkReasonOutgrown  = "outgrown by "
+	junkReasonDuplicate = "duplicate"
+)
+
+// FormatCopper spells out an amount of copper in coins, e.g. "1pp 2gp 5cp",
+// leaving out coins with no count.
+func FormatCopper(copper int) string {
+	if copper == 0 {
+		return "0cp"
+	}
+	sign := ""
+	if copper < 0 {
+		sign, copper = "-", -copper
+	}
+	var parts []string
+	for _, coin := range []struct {
+		value  int
+		suffix string
+	}{{CopperPerPlatinum, "pp"}, {CopperPerGold, "gp"}, {CopperPerSilver, "sp"}, {1, "cp"}} {
+		if count := copper / coin.value; count > 0 {
+			parts = append(parts, strconv.Itoa(count)+coin.suffix)
+			copper %= coin.value
+		}
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+// VendorValue is what selling the whole stack is worth.
+func (item Item) VendorValue() int {
+	return item.BaseValueCopper * max(item.Quantity, 1)
+}
+
+// JunkOptions tells FindJunk what counts as junk. Zero values turn a check
+// off.
+type JunkOptions struct {
+	// BelowLevel flags items with a minimum level under it.
+	BelowLevel int
+	// OutgrownBy flags gear whose holder is at least this many levels above
+	// the item's minimum level.
+	OutgrownBy int
+	// Duplicates flags all but one copy of the same equippable item.
+	Duplicates bool
+	// NonBTCOnly leaves out items bound to character.
+	NonBTCOnly bool
+}
+
+type JunkItem struct {
+	Item    Item
+	Reasons []string
+	Value   int
+}
+
+// ContainerValue totals the junk found in one container.
+type ContainerValue struct {
+	Holder    string
+	Container string
+	Count     int
+	Value     int
+}
+
+// FindJunk lists items worth considering for the vendor, most valuable
+// first, together with per-container totals. Equipped gear and favorites
+// are never junk.
+func FindJunk(items []Item, characters []CharacterInfo, options JunkOptions) (junk []JunkItem, totals []ContainerValue) {
+	levels := make(map[string]int, len(characters))
+	for _, character := range characters {
+		levels[character.Name] = character.Level
+	}
+	kept := func(item Item) bool {
+		return item.Container == equippedContainer || item.Annotation.Favorite
+	}
+	candidates := append([]Item(nil), items...)
+	// Visit kept items first, then by holder, so that the copy staying is
+	// the worn one if any, and duplicates are flagged consistently.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if kept(candidates[i]) != kept(candidates[j]) {
+			return kept(candidates[i])
+		}
+		return candidates[i].CharacterName < candidates[j].CharacterName
+	})
+
+	seen := make(map[string]bool)
+	for _, item := range candidates {
+		duplicate := false
+		if options.Duplicates && len(item.EquipsTo) > 0 {
+			key := item.Server + "/" + item.Name
+			duplicate = seen[key]
+			seen[key] = true
+		}
+		if kept(item) || (options.NonBTCOnly && item.BindingState() == BindingBoundToCharacter) {
+			continue
+		}
+		var reasons []string
+		if options.BelowLevel > 0 && item.MinimumLevel > 0 && item.MinimumLevel < options.BelowLevel {
+			reasons = append(reasons, junkReasonLowLevel)
+		}
+		if level := levels[item.CharacterName]; options.OutgrownBy > 0 && len(item.EquipsTo) > 0 && level > 0 && level-item.MinimumLevel >= options.OutgrownBy {
+			reasons = append(reasons, junkReasonOutgrown+item.CharacterName)
+		}
+		if duplicate {
+			reasons = append(reasons, junkReasonDuplicate)
+		}
+		if len(reasons) > 0 {
+			junk = append(junk, JunkItem{Item: item, Reasons: reasons, Value: item.VendorValue()})
+		}
+	}
+	sort.SliceStable(junk, func(i, j int) bool {
+		if junk[i].Value != junk[j].Value {
+			return junk[i].Value > junk[j].Value
+		}
+		return junk[i].Item.Name < junk[j].Item.Name
+	})
+
+	byContainer := make(map[[2]string]*ContainerValue)
+	for _, entry := range junk {
+		key := [2]string{entry.Item.CharacterName, entry.Item.Container}
+		total, exists := byContainer[key]
+		if !exists {
+			total = &ContainerValue{Holder: key[0], Container: key[1]}
+			byContainer[key] = total
+		}
+		total.Count++
+		total.Value += entry.Value
+	}
+	for _, total := range byContainer {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Value != totals[j].Value {
+			return totals[i].Value > totals[j].Value
+		}
+		return totals[i].Holder+totals[i].Container < totals[j].Holder+totals[j].Container
+	})
+	return junk, totals
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFormatCopper(t *testing.T) {
+	tests := []struct {
+		copper int
+		want   string
+	}{
+		{copper: 0, want: "0cp"},
+		{copper: 7, want: "7cp"},
+		{copper: 250, want: "2gp 5sp"},
+		{copper: 12345, want: "12pp 3gp 4sp 5cp"},
+		{copper: -1005, want: "-1pp 5cp"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, FormatCopper(tt.copper), tt.want)
+	}
+}
+
+func TestFindJunk(t *testing.T) {
+	items := []Item{
+		{Name: "Old Ring", CharacterName: "CharA", Container: "Inventory", MinimumLevel: 3, BaseValueCopper: 500, Quantity: 1, EquipsTo: []string{"Finger"}},
+		{Name: "Potion", CharacterName: "CharA", Container: "Inventory", MinimumLevel: 1, BaseValueCopper: 10, Quantity: 20},
+		{Name: "Helm", CharacterName: "CharA", Container: "Equipped", MinimumLevel: 20, EquipsTo: []string{"Head"}},
+		{Name: "Helm", CharacterName: "CharB", Container: "Inventory", MinimumLevel: 20, BaseValueCopper: 900, Quantity: 1, EquipsTo: []string{"Head"}},
+		{Name: "Boots", CharacterName: "CharA", Container: "Inventory", MinimumLevel: 15, BaseValueCopper: 300, Quantity: 1, EquipsTo: []string{"Feet"},
+			Binding: "BoundToCharacter"},
+		{Name: "Lucky Charm", CharacterName: "CharA", Container: "Inventory", MinimumLevel: 1, BaseValueCopper: 5, Quantity: 1,
+			Annotation: Annotation{Favorite: true}},
+	}
+	characters := []CharacterInfo{{Name: "CharA", Level: 28}}
+
+	junk, totals := FindJunk(items, characters, JunkOptions{BelowLevel: 10, OutgrownBy: 10, Duplicates: true})
+	var names []string
+	for _, entry := range junk {
+		names = append(names, entry.Item.Name)
+	}
+	// The worn helm stays; the copy in CharB's bag is the duplicate.
+	assert.DeepEqual(t, names, []string{"Helm", "Old Ring", "Boots", "Potion"})
+	assert.DeepEqual(t, junk[1].Reasons, []string{"low level", "outgrown by CharA"})
+	assert.DeepEqual(t, junk[0].Reasons, []string{"duplicate"})
+	assert.Equal(t, junk[3].Value, 200)
+	assert.DeepEqual(t, totals, []ContainerValue{
+		{Holder: "CharA", Container: "Inventory", Count: 3, Value: 1000},
+		{Holder: "CharB", Container: "Inventory", Count: 1, Value: 900},
+	})
+
+	junk, _ = FindJunk(items, characters, JunkOptions{OutgrownBy: 10, NonBTCOnly: true})
+	assert.Equal(t, len(junk), 1)
+	assert.Equal(t, junk[0].Item.Name, "Old Ring")
+}
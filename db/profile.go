@@ -0,0 +1,75 @@
+package db
+
+import (
+	"strings"
+)
+
+// CharacterProfile is what the player knows about a character that Trove
+// does not record. Profiles match a character by CharacterID when set,
+// otherwise by name; zero fields keep the inferred values.
+type CharacterProfile struct {
+	CharacterID   int64    `json:"character_id,omitempty"`
+	Name          string   `json:"name"`
+	Level         int      `json:"level,omitempty"`
+	Classes       []string `json:"classes,omitempty"`
+	Race          string   `json:"race,omitempty"`
+	Proficiencies []string `json:"proficiencies,omitempty"`
+	WeaponTypes   []string `json:"weapon_types,omitempty"`
+	ArmorTypes    []string `json:"armor_types,omitempty"`
+}
+
+func (p CharacterProfile) Matches(character CharacterInfo) bool {
+	if p.CharacterID != 0 && character.CharacterID != 0 {
+		return p.CharacterID == character.CharacterID
+	}
+	return strings.EqualFold(p.Name, character.Name)
+}
+
+// WithProfile returns c with the non-zero fields of profile applied.
+func (c CharacterInfo) WithProfile(profile CharacterProfile) CharacterInfo {
+	if profile.Level > 0 {
+		c.Level = profile.Level
+	}
+	if len(profile.Classes) > 0 {
+		c.Classes = profile.Classes
+	}
+	if profile.Race != "" {
+		c.Race = profile.Race
+	}
+	if len(profile.Proficiencies) > 0 {
+		c.Proficiencies = profile.Proficiencies
+	}
+	if len(profile.WeaponTypes) > 0 {
+		c.WeaponTypes = profile.WeaponTypes
+	}
+	if len(profile.ArmorTypes) > 0 {
+		c.ArmorTypes = profile.ArmorTypes
+	}
+	return c
+}
+
+// ApplyProfiles overrides inferred character info with matching profiles.
+func ApplyProfiles(characters []CharacterInfo, profiles []CharacterProfile) []CharacterInfo {
+	applied := make([]CharacterInfo, len(characters))
+	for index, character := range characters {
+		for _, profile := range profiles {
+			if profile.Matches(character) {
+				character = character.WithProfile(profile)
+				break
+			}
+		}
+		applied[index] = character
+	}
+	return applied
+}
+
+// FindProfile returns the profile for character, or a new one keyed by its
+// CharacterID and name.
+func FindProfile(profiles []CharacterProfile, character CharacterInfo) (profile CharacterProfile, found bool) {
+	for _, profile = range profiles {
+		if profile.Matches(character) {
+			return profile, true
+		}
+	}
+	return CharacterProfile{CharacterID: character.CharacterID, Name: character.Name}, false
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestApplyProfiles(t *testing.T) {
+	characters := []CharacterInfo{
+		{Name: "Tank", CharacterID: 1, Level: 12, ArmorTypes: []string{"Heavy"}},
+		{Name: "Caster", CharacterID: 2, Level: 8},
+		{Name: "Renamed", CharacterID: 3},
+	}
+	profiles := []CharacterProfile{
+		{Name: "caster", Level: 20, Classes: []string{"Wizard"}, Race: "Elf"},
+		{CharacterID: 3, Name: "Old Name", WeaponTypes: []string{"Khopesh"}},
+		{CharacterID: 9, Name: "Tank", Level: 30},
+	}
+
+	applied := ApplyProfiles(characters, profiles)
+	// Tank's profile entry has another CharacterID, so it does not apply.
+	assert.DeepEqual(t, applied[0], characters[0])
+	assert.DeepEqual(t, applied[1], CharacterInfo{Name: "Caster", CharacterID: 2, Level: 20, Classes: []string{"Wizard"}, Race: "Elf"})
+	assert.DeepEqual(t, applied[2].WeaponTypes, []string{"Khopesh"})
+
+	profile, found := FindProfile(profiles, CharacterInfo{Name: "Healer", CharacterID: 4})
+	assert.Assert(t, !found)
+	assert.DeepEqual(t, profile, CharacterProfile{CharacterID: 4, Name: "Healer"})
+}
+
+func TestFilterUsableBy(t *testing.T) {
+	character := CharacterInfo{
+		Name:          "Tank",
+		CharacterID:   1,
+		Level:         20,
+		Proficiencies: []string{"Martial Weapon Proficiency"},
+		WeaponTypes:   []string{"Khopesh"},
+	}
+	items := []Item{
+		{Name: "Longsword", EquipsTo: []string{"Hand"}, Proficiency: "Martial Weapon Proficiency", WeaponType: "Longsword"},
+		{Name: "Khopesh", EquipsTo: []string{"Hand"}, Proficiency: "Exotic Weapon Proficiency", WeaponType: "Khopesh"},
+		{Name: "Handwraps", EquipsTo: []string{"Hand"}, Proficiency: "Exotic Weapon Proficiency", WeaponType: "Handwraps"},
+		{Name: "Epic Ring", EquipsTo: []string{"Finger"}, MinimumLevel: 29},
+		{Name: "Other's Ring", EquipsTo: []string{"Finger"}, OwnerID: 2, Binding: "BoundToCharacter"},
+		{Name: "Ingredient"},
+	}
+
+	assert.DeepEqual(t, itemNames(FilterUsableBy(items, character)), []string{"Longsword", "Khopesh"})
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Recipe is a crafting recipe from the recipes file, e.g. one step of the
+// Green Steel ladder.
+type Recipe struct {
+	Name        string             `json:"name"            yaml:"name"`
+	Group       string             `json:"group,omitempty" yaml:"group,omitempty"`
+	Ingredients []IngredientTarget `json:"ingredients"     yaml:"ingredients"`
+}
+
+func (r Recipe) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("recipe without a name")
+	}
+	if len(r.Ingredients) == 0 {
+		return fmt.Errorf("recipe %q has no ingredients", r.Name)
+	}
+	for _, ingredient := range r.Ingredients {
+		if strings.TrimSpace(ingredient.Name) == "" || ingredient.Quantity <= 0 {
+			return fmt.Errorf("recipe %q: ingredients need a name and a positive quantity", r.Name)
+		}
+	}
+	return nil
+}
+
+// Handover is an ingredient stack the crafter has to get from another holder.
+type Handover struct {
+	From       string
+	Ingredient string
+	Quantity   int
+}
+
+type RecipeCheck struct {
+	Recipe      Recipe
+	Ingredients []IngredientTotal
+	// Crafter is the character already holding most of the ingredients.
+	Crafter   string
+	Handovers []Handover
+}
+
+func (c RecipeCheck) Completable() bool {
+	for _, ingredient := range c.Ingredients {
+		if ingredient.Missing() > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckRecipes matches recipes against aggregated ingredient totals, keeping
+// the recipes file order.
+func CheckRecipes(recipes []Recipe, totals []IngredientTotal) []RecipeCheck {
+	checks := make([]RecipeCheck, 0, len(recipes))
+	for _, recipe := range recipes {
+		check := RecipeCheck{Recipe: recipe, Ingredients: ApplyTargets(totals, recipe.Ingredients)}
+		check.Crafter = pickCrafter(check.Ingredients)
+		for _, ingredient := range check.Ingredients {
+			check.Handovers = append(check.Handovers, handovers(ingredient, check.Crafter)...)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// pickCrafter returns the character holding the most usable units. Account
+// banks cannot craft, so they never are the crafter.
+func pickCrafter(ingredients []IngredientTotal) string {
+	units := make(map[string]int)
+	for _, ingredient := range ingredients {
+		for _, holding := range ingredient.Holders {
+			if holding.Holder != accountSharedBankName && holding.Holder != accountCraftingBankName {
+				units[holding.Holder] += min(holding.Quantity, ingredient.Target)
+			}
+		}
+	}
+	crafter := ""
+	for holder, count := range units {
+		if count > units[crafter] || (count == units[crafter] && holder < crafter) {
+			crafter = holder
+		}
+	}
+	return crafter
+}
+
+// handovers takes what the crafter lacks from the largest other stacks first.
+func handovers(ingredient IngredientTotal, crafter string) []Handover {
+	needed := ingredient.Target
+	holdings := append([]IngredientHolding(nil), ingredient.Holders...)
+	sort.SliceStable(holdings, func(i, j int) bool {
+		if (holdings[i].Holder == crafter) != (holdings[j].Holder == crafter) {
+			return holdings[i].Holder == crafter
+		}
+		return holdings[i].Quantity > holdings[j].Quantity
+	})
+	var result []Handover
+	for _, holding := range holdings {
+		if needed <= 0 {
+			break
+		}
+		quantity := min(holding.Quantity, needed)
+		needed -= quantity
+		if holding.Holder != crafter {
+			result = append(result, Handover{From: holding.Holder, Ingredient: ingredient.Name, Quantity: quantity})
+		}
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckRecipes(t *testing.T) {
+	items := []Item{
+		{Name: "Cannith Essence", ItemType: "Ingredient", CharacterName: "CharA", Quantity: 40},
+		{Name: "Cannith Essence", ItemType: "Ingredient", CharacterName: "CharB", Quantity: 30},
+		{Name: "Cannith Essence", ItemType: "Ingredient", CharacterName: "CharC", Quantity: 50},
+		{Name: "Shroud Gem", CharacterName: "Account (Crafting Bank)", Container: "CraftingBank", Quantity: 3},
+	}
+	recipes := []Recipe{
+		{Name: "Bind Shard", Ingredients: []IngredientTarget{{Name: "Cannith Essence", Quantity: 100}, {Name: "Shroud Gem", Quantity: 2}}},
+		{Name: "Big Bind", Ingredients: []IngredientTarget{{Name: "Shroud Gem", Quantity: 5}, {Name: "Dragon Scale", Quantity: 1}}},
+	}
+
+	checks := CheckRecipes(recipes, AggregateIngredients(items))
+	assert.Equal(t, len(checks), 2)
+
+	shard := checks[0]
+	assert.Assert(t, shard.Completable())
+	assert.Equal(t, shard.Crafter, "CharC")
+	assert.DeepEqual(t, shard.Handovers, []Handover{
+		{From: "CharA", Ingredient: "Cannith Essence", Quantity: 40},
+		{From: "CharB", Ingredient: "Cannith Essence", Quantity: 10},
+		{From: "Account (Crafting Bank)", Ingredient: "Shroud Gem", Quantity: 2},
+	})
+
+	big := checks[1]
+	assert.Assert(t, !big.Completable())
+	assert.Equal(t, big.Crafter, "")
+	assert.Equal(t, big.Ingredients[0].Missing(), 2)
+	assert.Equal(t, big.Ingredients[1].Missing(), 1)
+}
+
+func TestRecipeValidate(t *testing.T) {
+	assert.NilError(t, Recipe{Name: "Ok", Ingredients: []IngredientTarget{{Name: "Gem", Quantity: 1}}}.Validate())
+	assert.ErrorContains(t, Recipe{Ingredients: []IngredientTarget{{Name: "Gem", Quantity: 1}}}.Validate(), "without a name")
+	assert.ErrorContains(t, Recipe{Name: "Empty"}.Validate(), "no ingredients")
+	assert.ErrorContains(t, Recipe{Name: "Bad", Ingredients: []IngredientTarget{{Name: "Gem"}}}.Validate(), "positive quantity")
+}
@@ -0,0 +1,411 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// AnyTab leaves the destination tab up to the planner.
+	AnyTab = -1
+	// OwnerHolder as a rule target means the character the item is bound to.
+	OwnerHolder = "owner"
+
+	ruleArrow          = "=>"
+	ruleCommentPrefix  = "#"
+	ruleMatchAll       = "*"
+	inventoryContainer = "Inventory"
+)
+
+var ErrNoRuleTarget = errors.New("rule has no target holder")
+
+// MoveTarget is where a rule wants its items. Empty Container and AnyTab
+// leave those up to the planner.
+type MoveTarget struct {
+	Holder    string
+	Container string
+	Tab       int
+}
+
+// MoveRule is one line of a reorganization rule set, e.g.
+//
+//	type=Augment => Account (Shared Bank), SharedBank, 2
+//	binding=BoundToCharacter => owner
+//
+// Match terms are comma separated key=value pairs that must all hold; keys
+// are type, subtype, name (substring), binding, set, equips, holder and
+// container. A lone * matches every item.
+type MoveRule struct {
+	Line   int
+	Text   string
+	Terms  map[string]string
+	Target MoveTarget
+}
+
+type Location struct {
+	Holder    string
+	Container string
+	Tab       int
+	Row       int
+	Column    int
+	// HasSlot is false when no free cell position is known for the
+	// destination, e.g. a container without any items yet.
+	HasSlot bool
+}
+
+type Move struct {
+	Item Item
+	Rule MoveRule
+	From Location
+	To   Location
+}
+
+type SkippedMove struct {
+	Item   Item
+	Rule   MoveRule
+	Reason string
+}
+
+var ruleKeys = map[string]func(Item, string) bool{
+	"type":    func(item Item, value string) bool { return strings.EqualFold(item.ItemType, value) },
+	"subtype": func(item Item, value string) bool { return strings.EqualFold(item.ItemSubType, value) },
+	"binding": func(item Item, value string) bool {
+		binding, known := ParseBinding(value)
+		return known && item.BindingState() == binding
+	},
+	"set":       func(item Item, value string) bool { return strings.EqualFold(item.SetBonus1Name, value) },
+	"holder":    func(item Item, value string) bool { return strings.EqualFold(item.CharacterName, value) },
+	"container": func(item Item, value string) bool { return strings.EqualFold(item.Container, value) },
+	"name": func(item Item, value string) bool {
+		return strings.Contains(strings.ToLower(item.Name), strings.ToLower(value))
+	},
+	"equips": func(item Item, value string) bool {
+		for _, slot := range item.EquipsTo {
+			if strings.EqualFold(slot, value) {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// ParseMoveRules reads one rule per line; blank lines and lines starting
+// with # are ignored.
+func ParseMoveRules(text string) (rules []MoveRule, err error) {
+	for index, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ruleCommentPrefix) {
+			continue
+		}
+		rule, parseErr := parseMoveRule(line)
+		if parseErr != nil {
+			return nil, fmt.Errorf("line %d: %w", index+1, parseErr)
+		}
+		rule.Line = index + 1
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseMoveRule(line string) (rule MoveRule, err error) {
+	match, target, found := strings.Cut(line, ruleArrow)
+	if !found {
+		return rule, fmt.Errorf("missing %q in %q", ruleArrow, line)
+	}
+	rule.Text = line
+	rule.Terms = make(map[string]string)
+	if match = strings.TrimSpace(match); match != ruleMatchAll {
+		for term := range strings.SplitSeq(match, ",") {
+			key, value, hasValue := strings.Cut(term, "=")
+			key = strings.ToLower(strings.TrimSpace(key))
+			value = strings.TrimSpace(value)
+			if _, known := ruleKeys[key]; !known || !hasValue || value == "" {
+				return rule, fmt.Errorf("invalid match term %q", strings.TrimSpace(term))
+			}
+			if _, known := ParseBinding(value); key == "binding" && !known {
+				return rule, fmt.Errorf("unknown binding %q", value)
+			}
+			rule.Terms[key] = value
+		}
+	}
+
+	parts := strings.Split(target, ",")
+	rule.Target = MoveTarget{Holder: strings.TrimSpace(parts[0]), Tab: AnyTab}
+	if rule.Target.Holder == "" {
+		return rule, ErrNoRuleTarget
+	}
+	if len(parts) > 1 {
+		rule.Target.Container = strings.TrimSpace(parts[1])
+	}
+	if len(parts) > 2 {
+		if rule.Target.Tab, err = strconv.Atoi(strings.TrimSpace(parts[2])); err != nil {
+			return rule, fmt.Errorf("invalid tab %q: %w", strings.TrimSpace(parts[2]), err)
+		}
+	}
+	if len(parts) > 3 {
+		return rule, fmt.Errorf("too many target fields in %q", strings.TrimSpace(target))
+	}
+	return rule, nil
+}
+
+func (r MoveRule) Matches(item Item) bool {
+	for key, value := range r.Terms {
+		if !ruleKeys[key](item, value) {
+			return false
+		}
+	}
+	return true
+}
+
+type slotKey struct {
+	holder    string
+	container string
+	tab       int
+	row       int
+	column    int
+}
+
+// moveState tracks occupied cells and used capacity while moves are planned,
+// so that later moves see the effect of earlier ones.
+type moveState struct {
+	holders  []Holder
+	used     []int
+	occupied map[slotKey]bool
+	bounds   map[string]*gridBounds
+	tabs     map[string][]int
+}
+
+func newMoveState(items []Item, holders []Holder) *moveState {
+	state := &moveState{
+		holders:  holders,
+		used:     make([]int, len(holders)),
+		occupied: make(map[slotKey]bool),
+		bounds:   make(map[string]*gridBounds),
+		tabs:     make(map[string][]int),
+	}
+	for index, holder := range holders {
+		state.used[index] = holder.UsedCapacity
+	}
+	for _, item := range items {
+		state.occupied[itemSlot(item)] = true
+		bound, exists := state.bounds[item.Container]
+		if !exists {
+			state.bounds[item.Container] = &gridBounds{minRow: item.Row, maxRow: item.Row, minColumn: item.Column, maxColumn: item.Column}
+		} else {
+			bound.minRow = min(bound.minRow, item.Row)
+			bound.maxRow = max(bound.maxRow, item.Row)
+			bound.minColumn = min(bound.minColumn, item.Column)
+			bound.maxColumn = max(bound.maxColumn, item.Column)
+		}
+		tabKey := item.CharacterName + "\x00" + item.Container
+		if !slices.Contains(state.tabs[tabKey], item.Tab) {
+			state.tabs[tabKey] = append(state.tabs[tabKey], item.Tab)
+		}
+	}
+	for key := range state.tabs {
+		sort.Ints(state.tabs[key])
+	}
+	return state
+}
+
+func itemSlot(item Item) slotKey {
+	return slotKey{holder: item.CharacterName, container: item.Container, tab: item.Tab, row: item.Row, column: item.Column}
+}
+
+// holderIndex returns the holder record whose file holds container, falling
+// back to any record of that name; -1 if the holder is unknown.
+func (s *moveState) holderIndex(name, container string) int {
+	fallback := -1
+	for index, holder := range s.holders {
+		if holder.Name != name {
+			continue
+		}
+		if slices.Contains(holder.Containers, container) {
+			return index
+		}
+		if fallback < 0 {
+			fallback = index
+		}
+	}
+	return fallback
+}
+
+func (s *moveState) hasRoom(index int) bool {
+	holder := s.holders[index]
+	return holder.MaxCapacity <= 0 || s.used[index] < holder.MaxCapacity
+}
+
+// freeSlot finds the first empty cell of the destination, scanning tabs in
+// order and rows before columns.
+func (s *moveState) freeSlot(target MoveTarget) (location Location, found bool) {
+	location = Location{Holder: target.Holder, Container: target.Container, Tab: target.Tab}
+	bound, known := s.bounds[target.Container]
+	tabs := []int{target.Tab}
+	if target.Tab == AnyTab {
+		tabs = s.tabs[target.Holder+"\x00"+target.Container]
+	}
+	if !known || len(tabs) == 0 {
+		// Nothing to go by; trust the capacity check and let the game pick.
+		return location, true
+	}
+	for _, tab := range tabs {
+		for row := bound.minRow; row <= bound.maxRow; row++ {
+			for column := bound.minColumn; column <= bound.maxColumn; column++ {
+				if !s.occupied[slotKey{holder: target.Holder, container: target.Container, tab: tab, row: row, column: column}] {
+					location.Tab, location.Row, location.Column, location.HasSlot = tab, row, column, true
+					return location, true
+				}
+			}
+		}
+	}
+	return location, false
+}
+
+// PlanMoves returns the moves that bring items in line with rules; the first
+// matching rule decides where an item belongs. Moves respect binding (bound
+// to character items only go to their owner, bound to account items stay in
+// their account, nothing crosses servers) and holder capacity. Items waiting
+// for room are retried after other moves free space, so the returned order
+// is safe to follow step by step.
+func PlanMoves(items []Item, holders []Holder, rules []MoveRule) (moves []Move, skipped []SkippedMove) {
+	state := newMoveState(items, holders)
+
+	type pending struct {
+		item   Item
+		rule   MoveRule
+		target MoveTarget
+	}
+	var queue []pending
+	for _, item := range items {
+		for _, rule := range rules {
+			if !rule.Matches(item) {
+				continue
+			}
+			target, reason := resolveTarget(item, rule.Target, holders)
+			switch {
+			case reason != "":
+				skipped = append(skipped, SkippedMove{Item: item, Rule: rule, Reason: reason})
+			case !inPlace(item, target):
+				queue = append(queue, pending{item: item, rule: rule, target: target})
+			}
+			break
+		}
+	}
+
+	reasons := make([]string, len(queue))
+	for progress := true; progress && len(queue) > 0; {
+		progress = false
+		var waiting []pending
+		var waitingReasons []string
+		for _, next := range queue {
+			move, reason, retry := state.tryMove(next.item, next.target)
+			if reason == "" {
+				move.Rule = next.rule
+				moves = append(moves, move)
+				progress = true
+				continue
+			}
+			if !retry {
+				skipped = append(skipped, SkippedMove{Item: next.item, Rule: next.rule, Reason: reason})
+				continue
+			}
+			waiting = append(waiting, next)
+			waitingReasons = append(waitingReasons, reason)
+		}
+		queue, reasons = waiting, waitingReasons
+	}
+	for index, next := range queue {
+		skipped = append(skipped, SkippedMove{Item: next.item, Rule: next.rule, Reason: reasons[index]})
+	}
+	return moves, skipped
+}
+
+// resolveTarget fills in the owner holder and default container of target
+// for item, or explains why the item cannot go there.
+func resolveTarget(item Item, target MoveTarget, holders []Holder) (resolved MoveTarget, reason string) {
+	resolved = target
+	var destination *Holder
+	for index := range holders {
+		holder := &holders[index]
+		if target.Holder == OwnerHolder {
+			if holder.CharacterID != 0 && holder.CharacterID == item.OwnerID && holder.Server == item.Server {
+				destination = holder
+				break
+			}
+			continue
+		}
+		if strings.EqualFold(holder.Name, target.Holder) && (holder.Server == item.Server || holder.Server == "" || item.Server == "") {
+			destination = holder
+			if resolved.Container == "" || slices.Contains(holder.Containers, resolved.Container) {
+				break
+			}
+		}
+	}
+	if destination == nil {
+		if target.Holder == OwnerHolder {
+			return resolved, "owner not loaded"
+		}
+		return resolved, "unknown holder " + target.Holder + " on this server"
+	}
+	resolved.Holder = destination.Name
+	if resolved.Container == "" {
+		resolved.Container = defaultContainer(item, *destination)
+	}
+
+	_, reason = item.BindingAllows(destination.CharacterID, destination.SubscriptionAlias)
+	return resolved, reason
+}
+
+func defaultContainer(item Item, holder Holder) string {
+	if item.CharacterName == holder.Name && item.Container != "" {
+		return item.Container
+	}
+	if holder.CharacterID == 0 && len(holder.Containers) > 0 {
+		return holder.Containers[0]
+	}
+	return inventoryContainer
+}
+
+func inPlace(item Item, target MoveTarget) bool {
+	return item.CharacterName == target.Holder &&
+		item.Container == target.Container &&
+		(target.Tab == AnyTab || item.Tab == target.Tab)
+}
+
+// tryMove books item into target. retry is true when the move may succeed
+// once other moves free space.
+func (s *moveState) tryMove(item Item, target MoveTarget) (move Move, reason string, retry bool) {
+	to := s.holderIndex(target.Holder, target.Container)
+	if to < 0 {
+		return move, "unknown holder " + target.Holder, false
+	}
+	from := s.holderIndex(item.CharacterName, item.Container)
+	if from != to && !s.hasRoom(to) {
+		return move, fmt.Sprintf("%s is full (%d/%d)", target.Holder, s.used[to], s.holders[to].MaxCapacity), true
+	}
+	location, found := s.freeSlot(target)
+	if !found {
+		return move, "no free slot in " + target.Holder + " " + target.Container, true
+	}
+
+	s.occupied[itemSlot(item)] = false
+	if location.HasSlot {
+		s.occupied[slotKey{holder: location.Holder, container: location.Container, tab: location.Tab, row: location.Row, column: location.Column}] = true
+	}
+	if from != to {
+		if from >= 0 {
+			s.used[from]--
+		}
+		s.used[to]++
+	}
+	move = Move{
+		Item: item,
+		From: Location{Holder: item.CharacterName, Container: item.Container, Tab: item.Tab, Row: item.Row, Column: item.Column, HasSlot: true},
+		To:   location,
+	}
+	return move, "", false
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseMoveRules(t *testing.T) {
+	rules, err := ParseMoveRules(`
+# augments live in the shared bank
+type=Augment, name=Topaz => Account (Shared Bank), SharedBank, 2
+* => owner
+`)
+	assert.NilError(t, err)
+	assert.Equal(t, len(rules), 2)
+	assert.Equal(t, rules[0].Line, 3)
+	assert.DeepEqual(t, rules[0].Terms, map[string]string{"type": "Augment", "name": "Topaz"})
+	assert.DeepEqual(t, rules[0].Target, MoveTarget{Holder: "Account (Shared Bank)", Container: "SharedBank", Tab: 2})
+	assert.Equal(t, len(rules[1].Terms), 0)
+	assert.DeepEqual(t, rules[1].Target, MoveTarget{Holder: OwnerHolder, Tab: AnyTab})
+
+	tests := []struct {
+		name string
+		text string
+		err  string
+	}{
+		{name: "no arrow", text: "type=Augment", err: `line 1: missing "=>"`},
+		{name: "unknown key", text: "colour=Red => CharA", err: `line 1: invalid match term "colour=Red"`},
+		{name: "unknown binding", text: "binding=soulbound => owner", err: `line 1: unknown binding "soulbound"`},
+		{name: "no target", text: "\ntype=Gem =>", err: "line 2: rule has no target holder"},
+		{name: "bad tab", text: "type=Gem => CharA, Inventory, two", err: `line 1: invalid tab "two"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMoveRules(tt.text)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestPlanMoves(t *testing.T) {
+	const sharedBank = "Account (Shared Bank)"
+	holders := []Holder{
+		{Name: "CharA", CharacterID: 1, Server: "Argonnessen", Containers: []string{"Inventory"}, UsedCapacity: 4, MaxCapacity: 5},
+		{Name: "CharB", CharacterID: 2, Server: "Argonnessen", Containers: []string{"Inventory"}, UsedCapacity: 1, MaxCapacity: 10},
+		{Name: sharedBank, Server: "Argonnessen", Containers: []string{"SharedBank"}, UsedCapacity: 2, MaxCapacity: 3},
+	}
+	items := []Item{
+		{Name: "Boots", OwnerID: 2, Binding: "BoundToCharacter", CharacterName: "CharA", Container: "Inventory", Tab: 1, Row: 1, Column: 0},
+		{Name: "Topaz", ItemType: "Augment", CharacterName: "CharA", Container: "Inventory", Tab: 1, Row: 0, Column: 0},
+		{Name: "Ruby", ItemType: "Augment", CharacterName: "CharA", Container: "Inventory", Tab: 1, Row: 0, Column: 1},
+		{Name: "Ring", OwnerID: 1, Binding: "BoundToCharacter", CharacterName: "CharA", Container: "Inventory", Tab: 1, Row: 1, Column: 1},
+		{Name: "Gem", ItemType: "Gem", CharacterName: sharedBank, Container: "SharedBank", Tab: 2, Row: 0, Column: 0},
+		{Name: "Tome", ItemType: "Collectible", CharacterName: sharedBank, Container: "SharedBank", Tab: 2, Row: 1, Column: 1},
+		{Name: "Potion", CharacterName: "CharB", Container: "Inventory", Tab: 1, Row: 0, Column: 0},
+	}
+	for index := range items {
+		items[index].Server = "Argonnessen"
+	}
+	rules, err := ParseMoveRules(`
+binding=BoundToCharacter, name=Ring => Account (Shared Bank)
+binding=btc => owner
+type=Augment => Account (Shared Bank), SharedBank, 2
+type=Gem => CharA, Inventory
+type=Collectible => Account (Shared Bank)
+name=Potion => Nobody
+`)
+	assert.NilError(t, err)
+
+	moves, skipped := PlanMoves(items, holders, rules)
+
+	var names []string
+	var destinations []Location
+	for _, move := range moves {
+		names = append(names, move.Item.Name)
+		destinations = append(destinations, move.To)
+	}
+	// The shared bank is full after Topaz; Ruby waits until Gem moves out.
+	assert.DeepEqual(t, names, []string{"Boots", "Topaz", "Gem", "Ruby"})
+	assert.DeepEqual(t, destinations, []Location{
+		{Holder: "CharB", Container: "Inventory", Tab: 1, Row: 0, Column: 1, HasSlot: true},
+		{Holder: sharedBank, Container: "SharedBank", Tab: 2, Row: 0, Column: 1, HasSlot: true},
+		{Holder: "CharA", Container: "Inventory", Tab: 1, Row: 0, Column: 0, HasSlot: true},
+		{Holder: sharedBank, Container: "SharedBank", Tab: 2, Row: 0, Column: 0, HasSlot: true},
+	})
+	assert.DeepEqual(t, moves[0].From, Location{Holder: "CharA", Container: "Inventory", Tab: 1, Row: 1, Column: 0, HasSlot: true})
+
+	assert.Equal(t, len(skipped), 2)
+	assert.Equal(t, skipped[0].Item.Name, "Ring")
+	assert.Equal(t, skipped[0].Reason, "bound to character, cannot go to an account bank")
+	assert.Equal(t, skipped[1].Item.Name, "Potion")
+	assert.Equal(t, skipped[1].Reason, "unknown holder Nobody on this server")
+}
+
+func TestPlanMovesNoRoom(t *testing.T) {
+	holders := []Holder{
+		{Name: "CharA", CharacterID: 1, Containers: []string{"Inventory"}, UsedCapacity: 1, MaxCapacity: 10},
+		{Name: "CharB", CharacterID: 2, Containers: []string{"Inventory"}, UsedCapacity: 1, MaxCapacity: 1},
+	}
+	items := []Item{
+		{Name: "Sword", CharacterName: "CharA", Container: "Inventory", Tab: 1},
+		{Name: "Potion", CharacterName: "CharB", Container: "Inventory", Tab: 1, Row: 1},
+	}
+	rules, err := ParseMoveRules("name=Sword => CharB")
+	assert.NilError(t, err)
+
+	moves, skipped := PlanMoves(items, holders, rules)
+	assert.Equal(t, len(moves), 0)
+	assert.Equal(t, len(skipped), 1)
+	assert.Equal(t, skipped[0].Reason, "CharB is full (1/1)")
+}
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// slotCapacity lists equipment slots that hold more than one item.
+var slotCapacity = map[string]int{
+	"Finger": 2,
+}
+
+var setPiecesPattern = regexp.MustCompile(`(?i)(\d+)\s*(?:pieces?|items?)`)
+
+type SetBonus struct {
+	Pieces      int
+	Description string
+}
+
+type SetHolding struct {
+	Holder     string
+	Items      []Item
+	Equippable int
+}
+
+type SetSummary struct {
+	Name       string
+	Bonuses    []SetBonus
+	Items      []Item
+	Holders    []SetHolding
+	Equippable int
+}
+
+// Reached returns the bonuses active when Equippable pieces are worn.
+func (s SetSummary) Reached() []SetBonus {
+	var reached []SetBonus
+	for _, bonus := range s.Bonuses {
+		if bonus.Pieces > 0 && bonus.Pieces <= s.Equippable {
+			reached = append(reached, bonus)
+		}
+	}
+	return reached
+}
+
+func GetUniqueSetNames(items []Item) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, item := range items {
+		if item.SetBonus1Name != "" && !seen[item.SetBonus1Name] {
+			seen[item.SetBonus1Name] = true
+			names = append(names, item.SetBonus1Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GroupItemsBySet summarizes owned set items per set name, per holder and
+// across all holders, assuming pieces could be moved onto one character.
+func GroupItemsBySet(items []Item) []SetSummary {
+	bySet := make(map[string]*SetSummary)
+	var names []string
+	for _, item := range items {
+		if item.SetBonus1Name == "" {
+			continue
+		}
+		summary, exists := bySet[item.SetBonus1Name]
+		if !exists {
+			summary = &SetSummary{Name: item.SetBonus1Name}
+			bySet[item.SetBonus1Name] = summary
+			names = append(names, item.SetBonus1Name)
+		}
+		if len(summary.Bonuses) == 0 {
+			summary.Bonuses = ParseSetBonuses(item.SetBonus1Description)
+		}
+		summary.Items = append(summary.Items, item)
+	}
+	sort.Strings(names)
+
+	summaries := make([]SetSummary, 0, len(names))
+	for _, name := range names {
+		summary := bySet[name]
+		sortByName(summary.Items)
+		summary.Equippable = EquippableTogether(summary.Items)
+
+		byHolder := make(map[string][]Item)
+		var holders []string
+		for _, item := range summary.Items {
+			if _, exists := byHolder[item.CharacterName]; !exists {
+				holders = append(holders, item.CharacterName)
+			}
+			byHolder[item.CharacterName] = append(byHolder[item.CharacterName], item)
+		}
+		sort.Strings(holders)
+		for _, holder := range holders {
+			summary.Holders = append(summary.Holders, SetHolding{
+				Holder:     holder,
+				Items:      byHolder[holder],
+				Equippable: EquippableTogether(byHolder[holder]),
+			})
+		}
+		summaries = append(summaries, *summary)
+	}
+	return summaries
+}
+
+// ParseSetBonuses reads the piece count from each bonus description, e.g.
+// "3 Pieces Equipped: +5 Insight bonus". Unparsable lines get Pieces 0.
+func ParseSetBonuses(descriptions []string) []SetBonus {
+	bonuses := make([]SetBonus, 0, len(descriptions))
+	for _, description := range descriptions {
+		bonus := SetBonus{Description: description}
+		if match := setPiecesPattern.FindStringSubmatch(description); match != nil {
+			bonus.Pieces, _ = strconv.Atoi(match[1])
+		}
+		bonuses = append(bonuses, bonus)
+	}
+	return bonuses
+}
+
+// EquippableTogether returns how many distinct pieces can be worn at once,
+// matching each item to a free slot among its EquipsTo values.
+func EquippableTogether(items []Item) int {
+	seenNames := make(map[string]bool)
+	var pieces [][]string
+	for _, item := range items {
+		if seenNames[item.Name] || len(item.EquipsTo) == 0 {
+			continue
+		}
+		seenNames[item.Name] = true
+		var slots []string
+		for _, slot := range item.EquipsTo {
+			capacity := max(slotCapacity[slot], 1)
+			for index := range capacity {
+				slots = append(slots, slot+"#"+strconv.Itoa(index))
+			}
+		}
+		pieces = append(pieces, slots)
+	}
+
+	assigned := make(map[string]int)
+	matched := 0
+	for piece := range pieces {
+		if assignSlot(piece, pieces, assigned, make(map[string]bool)) {
+			matched++
+		}
+	}
+	return matched
+}
+
+// assignSlot finds an augmenting path for piece in the piece/slot bipartite graph.
+func assignSlot(piece int, pieces [][]string, assigned map[string]int, visited map[string]bool) bool {
+	for _, slot := range pieces[piece] {
+		if visited[slot] {
+			continue
+		}
+		visited[slot] = true
+		holder, taken := assigned[slot]
+		if !taken || assignSlot(holder, pieces, assigned, visited) {
+			assigned[slot] = piece
+			return true
+		}
+	}
+	return false
+}
+
+func sortByName(items []Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGroupItemsBySet(t *testing.T) {
+	bonuses := []string{"2 Pieces Equipped: +1 Resistance", "3 Pieces Equipped: +2 Resistance", "Legendary set"}
+	items := []Item{
+		{Name: "Ring A", CharacterName: "CharA", EquipsTo: []string{"Finger"}, SetBonus1Name: "Guard", SetBonus1Description: bonuses},
+		{Name: "Ring B", CharacterName: "CharB", EquipsTo: []string{"Finger"}, SetBonus1Name: "Guard"},
+		{Name: "Ring C", CharacterName: "CharB", EquipsTo: []string{"Finger"}, SetBonus1Name: "Guard"},
+		{Name: "Ring C", CharacterName: "CharA", EquipsTo: []string{"Finger"}, SetBonus1Name: "Guard"},
+		{Name: "Helm", CharacterName: "CharA", EquipsTo: []string{"Head"}, SetBonus1Name: "Guard"},
+		{Name: "Plain Boots", CharacterName: "CharA", EquipsTo: []string{"Feet"}},
+		{Name: "Solo Cloak", CharacterName: "CharB", EquipsTo: []string{"Back"}, SetBonus1Name: "Alone"},
+	}
+
+	sets := GroupItemsBySet(items)
+	assert.Equal(t, len(sets), 2)
+	assert.Equal(t, sets[0].Name, "Alone")
+
+	guard := sets[1]
+	assert.Equal(t, guard.Name, "Guard")
+	assert.Equal(t, len(guard.Items), 5)
+	assert.DeepEqual(t, guard.Bonuses, []SetBonus{
+		{Pieces: 2, Description: bonuses[0]},
+		{Pieces: 3, Description: bonuses[1]},
+		{Pieces: 0, Description: bonuses[2]},
+	})
+	// Three distinct rings compete for two finger slots, plus the helm.
+	assert.Equal(t, guard.Equippable, 3)
+	assert.Equal(t, len(guard.Reached()), 2)
+
+	assert.Equal(t, len(guard.Holders), 2)
+	assert.Equal(t, guard.Holders[0].Holder, "CharA")
+	assert.Equal(t, guard.Holders[0].Equippable, 3)
+	assert.Equal(t, guard.Holders[1].Holder, "CharB")
+	assert.Equal(t, guard.Holders[1].Equippable, 2)
+
+	assert.DeepEqual(t, GetUniqueSetNames(items), []string{"Alone", "Guard"})
+}
+
+func TestEquippableTogether(t *testing.T) {
+	items := []Item{
+		{Name: "Either Hand", EquipsTo: []string{"MainHand", "OffHand"}},
+		{Name: "Main Only", EquipsTo: []string{"MainHand"}},
+		{Name: "Unwearable"},
+	}
+	assert.Equal(t, EquippableTogether(items), 2)
+}
@@ -0,0 +1,226 @@
+package db
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const equippedContainer = "Equipped"
+
+// proficiencyRank orders weapon proficiencies so that a character proficient
+// with martial weapons also counts as proficient with simple ones.
+var proficiencyRank = map[string]int{
+	"simple":  1,
+	"martial": 2,
+	"exotic":  3,
+}
+
+// armorRank orders armor so that heavier armor training covers lighter armor.
+var armorRank = map[string]int{
+	"cloth":  1,
+	"light":  2,
+	"medium": 3,
+	"heavy":  4,
+}
+
+// CharacterInfo is what is known about a character's ability to use gear.
+// Zero values mean unknown and do not restrict anything.
+type CharacterInfo struct {
+	Name              string
+	CharacterID       int64
+	Server            string
+	SubscriptionAlias string
+	Level             int
+	Classes           []string
+	Race              string
+	Proficiencies     []string
+	WeaponTypes       []string
+	ArmorTypes        []string
+}
+
+type TransferSuggestion struct {
+	Item Item
+	// Reason explains why the item is of no use where it is now.
+	Reason     string
+	Candidates []string
+}
+
+// InferCharacterInfo derives character info from the loaded holders and
+// what each character has equipped: the level is at least the highest
+// minimum level worn, and proficiencies and armor types are those of worn
+// gear. The result is a lower bound, sorted by name.
+func InferCharacterInfo(items []Item, holders []Holder) []CharacterInfo {
+	byName := make(map[string]*CharacterInfo)
+	var names []string
+	for _, holder := range holders {
+		if holder.CharacterID == 0 {
+			continue
+		}
+		if _, exists := byName[holder.Name]; !exists {
+			byName[holder.Name] = &CharacterInfo{
+				Name:              holder.Name,
+				CharacterID:       holder.CharacterID,
+				Server:            holder.Server,
+				SubscriptionAlias: holder.SubscriptionAlias,
+			}
+			names = append(names, holder.Name)
+		}
+	}
+	for _, item := range items {
+		info, exists := byName[item.CharacterName]
+		if !exists || item.Container != equippedContainer {
+			continue
+		}
+		info.Level = max(info.Level, item.MinimumLevel)
+		if item.Proficiency != "" && !slices.Contains(info.Proficiencies, item.Proficiency) {
+			info.Proficiencies = append(info.Proficiencies, item.Proficiency)
+		}
+		if item.ArmorType != "" && !slices.Contains(info.ArmorTypes, item.ArmorType) {
+			info.ArmorTypes = append(info.ArmorTypes, item.ArmorType)
+		}
+	}
+
+	sort.Strings(names)
+	infos := make([]CharacterInfo, 0, len(names))
+	for _, name := range names {
+		info := byName[name]
+		sort.Strings(info.Proficiencies)
+		sort.Strings(info.ArmorTypes)
+		infos = append(infos, *info)
+	}
+	return infos
+}
+
+// CanUse reports whether the character can equip item, and if not, why.
+func (c CharacterInfo) CanUse(item Item) (usable bool, reason string) {
+	if c.Level > 0 && item.MinimumLevel > c.Level {
+		return false, "needs level " + strconv.Itoa(item.MinimumLevel)
+	}
+	if !c.canWield(item) {
+		if item.Proficiency == "" {
+			return false, "lacks " + item.WeaponType + " proficiency"
+		}
+		return false, "lacks " + item.Proficiency
+	}
+	if item.ArmorType != "" && len(c.ArmorTypes) > 0 && !coveredBy(item.ArmorType, c.ArmorTypes, armorRank) {
+		return false, "cannot wear " + item.ArmorType + " armor"
+	}
+	return true, ""
+}
+
+// canWield checks weapon proficiency: a listed weapon type suffices, as does
+// a proficiency covering the item's. Without any weapon info, anything goes.
+func (c CharacterInfo) canWield(item Item) bool {
+	if item.Proficiency == "" && item.WeaponType == "" {
+		return true
+	}
+	if len(c.Proficiencies) == 0 && len(c.WeaponTypes) == 0 {
+		return true
+	}
+	for _, weaponType := range c.WeaponTypes {
+		if item.WeaponType != "" && strings.EqualFold(weaponType, item.WeaponType) {
+			return true
+		}
+	}
+	return item.Proficiency != "" && coveredBy(item.Proficiency, c.Proficiencies, proficiencyRank)
+}
+
+// CanReceive reports whether item can be handed to the character given its
+// binding: bound to character items stay with their owner, bound to account
+// items within their account, and nothing crosses servers.
+func (c CharacterInfo) CanReceive(item Item) bool {
+	if item.Server != "" && c.Server != "" && item.Server != c.Server {
+		return false
+	}
+	allowed, _ := item.BindingAllows(c.CharacterID, c.SubscriptionAlias)
+	return allowed
+}
+
+// coveredBy matches value against known by rank keyword (e.g. "Martial
+// Weapon Proficiency" covers "Simple Weapon Proficiency"), falling back to
+// an exact match for values without a known keyword.
+func coveredBy(value string, known []string, ranks map[string]int) bool {
+	valueRank := rankOf(value, ranks)
+	for _, candidate := range known {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+		if valueRank > 0 && rankOf(candidate, ranks) >= valueRank {
+			return true
+		}
+	}
+	return false
+}
+
+func rankOf(value string, ranks map[string]int) int {
+	lower := strings.ToLower(value)
+	rank := 0
+	for keyword, keywordRank := range ranks {
+		if strings.Contains(lower, keyword) {
+			rank = max(rank, keywordRank)
+		}
+	}
+	return rank
+}
+
+// SuggestTransfers lists gear that its current holder cannot use (or that
+// sits in an account bank) together with the characters that could equip it
+// and may receive it.
+func SuggestTransfers(items []Item, characters []CharacterInfo) []TransferSuggestion {
+	byName := make(map[string]CharacterInfo, len(characters))
+	for _, character := range characters {
+		byName[character.Name] = character
+	}
+
+	var suggestions []TransferSuggestion
+	for _, item := range items {
+		if len(item.EquipsTo) == 0 || item.BindingState() == BindingBoundToCharacter {
+			continue
+		}
+		reason := "stored in " + item.CharacterName
+		if current, isCharacter := byName[item.CharacterName]; isCharacter {
+			usable, why := current.CanUse(item)
+			if usable {
+				continue
+			}
+			reason = item.CharacterName + " " + why
+		}
+
+		var candidates []string
+		for _, character := range characters {
+			if character.Name == item.CharacterName || !character.CanReceive(item) {
+				continue
+			}
+			if usable, _ := character.CanUse(item); usable {
+				candidates = append(candidates, character.Name)
+			}
+		}
+		if len(candidates) > 0 {
+			suggestions = append(suggestions, TransferSuggestion{Item: item, Reason: reason, Candidates: candidates})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Item.Name != suggestions[j].Item.Name {
+			return suggestions[i].Item.Name < suggestions[j].Item.Name
+		}
+		return suggestions[i].Item.CharacterName < suggestions[j].Item.CharacterName
+	})
+	return suggestions
+}
+
+// FilterUsableBy keeps the items character could both receive and equip.
+// Items that equip nowhere, such as ingredients, are left out.
+func FilterUsableBy(items []Item, character CharacterInfo) []Item {
+	var usable []Item
+	for _, item := range items {
+		if len(item.EquipsTo) == 0 || !character.CanReceive(item) {
+			continue
+		}
+		if ok, _ := character.CanUse(item); ok {
+			usable = append(usable, item)
+		}
+	}
+	return usable
+}
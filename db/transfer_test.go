@@ -0,0 +1,81 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestInferCharacterInfo(t *testing.T) {
+	holders := []Holder{
+		{Name: "Tank", CharacterID: 1, Server: "Argonnessen", SubscriptionAlias: "main"},
+		{Name: "Tank", CharacterID: 1, Server: "Argonnessen", SubscriptionAlias: "main"},
+		{Name: "Account (Shared Bank)", Server: "Argonnessen"},
+	}
+	items := []Item{
+		{Name: "Plate", CharacterName: "Tank", Container: "Equipped", MinimumLevel: 12, ArmorType: "Heavy"},
+		{Name: "Axe", CharacterName: "Tank", Container: "Equipped", MinimumLevel: 15, Proficiency: "Martial Weapon Proficiency"},
+		{Name: "Spare Axe", CharacterName: "Tank", Container: "Inventory", MinimumLevel: 30, Proficiency: "Exotic Weapon Proficiency"},
+	}
+
+	assert.DeepEqual(t, InferCharacterInfo(items, holders), []CharacterInfo{{
+		Name:              "Tank",
+		CharacterID:       1,
+		Server:            "Argonnessen",
+		SubscriptionAlias: "main",
+		Level:             15,
+		Proficiencies:     []string{"Martial Weapon Proficiency"},
+		ArmorTypes:        []string{"Heavy"},
+	}})
+}
+
+func TestCharacterInfoCanUse(t *testing.T) {
+	fighter := CharacterInfo{Level: 20, Proficiencies: []string{"Martial Weapon Proficiency"}, ArmorTypes: []string{"Heavy"}}
+	tests := []struct {
+		name      string
+		character CharacterInfo
+		item      Item
+		reason    string
+	}{
+		{name: "covered", character: fighter, item: Item{MinimumLevel: 20, Proficiency: "Simple Weapon Proficiency", ArmorType: "Medium"}},
+		{name: "level", character: fighter, item: Item{MinimumLevel: 21}, reason: "needs level 21"},
+		{name: "proficiency", character: fighter, item: Item{Proficiency: "Exotic Weapon Proficiency"}, reason: "lacks Exotic Weapon Proficiency"},
+		{name: "armor", character: CharacterInfo{ArmorTypes: []string{"Light"}}, item: Item{ArmorType: "Heavy"}, reason: "cannot wear Heavy armor"},
+		{name: "unknown", character: CharacterInfo{}, item: Item{MinimumLevel: 30, Proficiency: "Exotic Weapon Proficiency", ArmorType: "Docent"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usable, reason := tt.character.CanUse(tt.item)
+			assert.Equal(t, usable, tt.reason == "")
+			assert.Equal(t, reason, tt.reason)
+		})
+	}
+}
+
+func TestSuggestTransfers(t *testing.T) {
+	characters := []CharacterInfo{
+		{Name: "Caster", CharacterID: 1, Server: "Argonnessen", SubscriptionAlias: "main", Level: 10, ArmorTypes: []string{"Cloth"}},
+		{Name: "Tank", CharacterID: 2, Server: "Argonnessen", SubscriptionAlias: "main", Level: 20, ArmorTypes: []string{"Heavy"}},
+		{Name: "Alt", CharacterID: 3, Server: "Argonnessen", SubscriptionAlias: "other", Level: 20, ArmorTypes: []string{"Heavy"}},
+		{Name: "Far", CharacterID: 4, Server: "Cannith", SubscriptionAlias: "main", Level: 30},
+	}
+	items := []Item{
+		{Name: "Plate", CharacterName: "Caster", EquipsTo: []string{"Body"}, ArmorType: "Heavy", Binding: "BoundToAccount", SubscriptionAlias: "main", Server: "Argonnessen"},
+		{Name: "Robe", CharacterName: "Caster", EquipsTo: []string{"Body"}, ArmorType: "Cloth", Server: "Argonnessen"},
+		{Name: "Ring", CharacterName: "Account (Shared Bank)", EquipsTo: []string{"Finger"}, MinimumLevel: 15, Server: "Argonnessen"},
+		{Name: "Helm", CharacterName: "Caster", EquipsTo: []string{"Head"}, MinimumLevel: 25, OwnerID: 1, Binding: "BoundToCharacter", Server: "Argonnessen"},
+		{Name: "Gem", CharacterName: "Account (Shared Bank)", Server: "Argonnessen"},
+		// An unrecognized binding is never moved.
+		{Name: "Odd Ring", CharacterName: "Account (Shared Bank)", EquipsTo: []string{"Finger"}, Binding: "Soulbound", Server: "Argonnessen"},
+	}
+
+	suggestions := SuggestTransfers(items, characters)
+	assert.Equal(t, len(suggestions), 2)
+	assert.Equal(t, suggestions[0].Item.Name, "Plate")
+	assert.Equal(t, suggestions[0].Reason, "Caster cannot wear Heavy armor")
+	// Alt is on another account, so bound to account plate can only go to Tank.
+	assert.DeepEqual(t, suggestions[0].Candidates, []string{"Tank"})
+	assert.Equal(t, suggestions[1].Item.Name, "Ring")
+	assert.Equal(t, suggestions[1].Reason, "stored in Account (Shared Bank)")
+	assert.DeepEqual(t, suggestions[1].Candidates, []string{"Tank", "Alt"})
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var statValuePattern = regexp.MustCompile(`[+-]?\d+`)
+
+type Upgrade struct {
+	Item Item
+	// Stats holds the item's value for each chosen stat, in order.
+	Stats []int
+	// Better is set when the item beats the best equippable item of its slot
+	// in at least one chosen stat without falling behind in any.
+	Better bool
+}
+
+type UpgradeSlot struct {
+	Slot string
+	// Current is the best value per chosen stat among items of this slot
+	// equippable at the current level.
+	Current  []int
+	Upgrades []Upgrade
+}
+
+// StatValue sums the numeric bonuses of the effects that mention stat, e.g.
+// "Insightful Strength +3" and "Strength +7" give 10 for "strength".
+func StatValue(item Item, stat string) int {
+	stat = strings.ToLower(strings.TrimSpace(stat))
+	if stat == "" {
+		return 0
+	}
+	total := 0
+	for _, effect := range item.Effects {
+		if !strings.Contains(strings.ToLower(effect.Name), stat) {
+			continue
+		}
+		match := statValuePattern.FindString(effect.Name)
+		if match == "" {
+			match = statValuePattern.FindString(effect.Description)
+		}
+		if value, err := strconv.Atoi(match); err == nil {
+			total += value
+		}
+	}
+	return total
+}
+
+func statValues(item Item, stats []string) []int {
+	values := make([]int, len(stats))
+	for index, stat := range stats {
+		values[index] = StatValue(item, stat)
+	}
+	return values
+}
+
+// FindUpgrades lists items that become equippable within the next levels
+// levels after level, grouped by EquipsTo slot and sorted by minimum level.
+// Items should already be narrowed to what the character can use.
+func FindUpgrades(items []Item, level, levels int, stats []string) []UpgradeSlot {
+	upcoming := FilterItems(items, FilterAll, FilterAll, FilterAll, "", level+1, level+levels, FilterAll, FilterAll)
+	current := FilterItems(items, FilterAll, FilterAll, FilterAll, "", 0, level, FilterAll, FilterAll)
+
+	best := make(map[string][]int)
+	for _, item := range current {
+		values := statValues(item, stats)
+		for _, slot := range item.EquipsTo {
+			slotBest, exists := best[slot]
+			if !exists {
+				best[slot] = append([]int(nil), values...)
+				continue
+			}
+			for index, value := range values {
+				slotBest[index] = max(slotBest[index], value)
+			}
+		}
+	}
+
+	bySlot := make(map[string]*UpgradeSlot)
+	var slots []string
+	for _, item := range upcoming {
+		values := statValues(item, stats)
+		for _, slot := range item.EquipsTo {
+			group, exists := bySlot[slot]
+			if !exists {
+				group = &UpgradeSlot{Slot: slot, Current: best[slot]}
+				if group.Current == nil {
+					group.Current = make([]int, len(stats))
+				}
+				bySlot[slot] = group
+				slots = append(slots, slot)
+			}
+			group.Upgrades = append(group.Upgrades, Upgrade{Item: item, Stats: values, Better: dominates(values, group.Current)})
+		}
+	}
+
+	sort.Strings(slots)
+	result := make([]UpgradeSlot, 0, len(slots))
+	for _, slot := range slots {
+		group := bySlot[slot]
+		sort.SliceStable(group.Upgrades, func(i, j int) bool {
+			if group.Upgrades[i].Item.MinimumLevel != group.Upgrades[j].Item.MinimumLevel {
+				return group.Upgrades[i].Item.MinimumLevel < group.Upgrades[j].Item.MinimumLevel
+			}
+			return group.Upgrades[i].Item.Name < group.Upgrades[j].Item.Name
+		})
+		result = append(result, *group)
+	}
+	return result
+}
+
+// dominates reports whether values are at least current everywhere and
+// higher somewhere; with no stats chosen nothing dominates.
+func dominates(values, current []int) bool {
+	higher := false
+	for index, value := range values {
+		if value < current[index] {
+			return false
+		}
+		if value > current[index] {
+			higher = true
+		}
+	}
+	return higher
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestStatValue(t *testing.T) {
+	item := Item{Effects: []Effect{
+		{Name: "Strength +7"},
+		{Name: "Insightful Strength", Description: "+3 Insight bonus to Strength"},
+		{Name: "Doublestrike 10%"},
+		{Name: "Constitution +5"},
+	}}
+	assert.Equal(t, StatValue(item, "strength"), 10)
+	assert.Equal(t, StatValue(item, "Doublestrike"), 10)
+	assert.Equal(t, StatValue(item, "Wisdom"), 0)
+	assert.Equal(t, StatValue(item, " "), 0)
+}
+
+func TestFindUpgrades(t *testing.T) {
+	items := []Item{
+		{Name: "Old Belt", EquipsTo: []string{"Waist"}, MinimumLevel: 8, Effects: []Effect{{Name: "Strength +4"}, {Name: "Constitution +4"}}},
+		{Name: "New Belt", EquipsTo: []string{"Waist"}, MinimumLevel: 11, Effects: []Effect{{Name: "Strength +5"}, {Name: "Constitution +4"}}},
+		{Name: "Side Belt", EquipsTo: []string{"Waist"}, MinimumLevel: 12, Effects: []Effect{{Name: "Strength +6"}}},
+		{Name: "Ring", EquipsTo: []string{"Finger"}, MinimumLevel: 12, Effects: []Effect{{Name: "Constitution +1"}}},
+		{Name: "Far Belt", EquipsTo: []string{"Waist"}, MinimumLevel: 14, Effects: []Effect{{Name: "Strength +9"}}},
+		{Name: "Gem", MinimumLevel: 11},
+	}
+
+	slots := FindUpgrades(items, 10, 3, []string{"Strength", "Constitution"})
+	assert.Equal(t, len(slots), 2)
+
+	assert.Equal(t, slots[0].Slot, "Finger")
+	assert.DeepEqual(t, slots[0].Current, []int{0, 0})
+	assert.Assert(t, slots[0].Upgrades[0].Better)
+
+	waist := slots[1]
+	assert.Equal(t, waist.Slot, "Waist")
+	assert.DeepEqual(t, waist.Current, []int{4, 4})
+	assert.Equal(t, len(waist.Upgrades), 2)
+	assert.Equal(t, waist.Upgrades[0].Item.Name, "New Belt")
+	assert.DeepEqual(t, waist.Upgrades[0].Stats, []int{5, 4})
+	assert.Assert(t, waist.Upgrades[0].Better)
+	// More strength but no constitution is not strictly better.
+	assert.Equal(t, waist.Upgrades[1].Item.Name, "Side Belt")
+	assert.Assert(t, !waist.Upgrades[1].Better)
+
+	for _, upgrade := range FindUpgrades(items, 10, 3, nil)[1].Upgrades {
+		assert.Assert(t, !upgrade.Better)
+	}
+}
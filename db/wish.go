@@ -0,0 +1,68 @@
+package db
+
+import (
+	"strings"
+	"time"
+)
+
+// maxWishLevel covers every item level in the game.
+const maxWishLevel = 40
+
+// WishLocation is where an item matching a wish was seen.
+type WishLocation struct {
+	Identity  string `json:"identity"`
+	Name      string `json:"name"`
+	Holder    string `json:"holder"`
+	Container string `json:"container"`
+}
+
+// Wish is a wish list entry: an item name, or a full text search as in the
+// item list when Exact is false.
+type Wish struct {
+	Query       string         `json:"query"`
+	Exact       bool           `json:"exact,omitempty"`
+	Added       time.Time      `json:"added"`
+	FulfilledAt time.Time      `json:"fulfilled_at,omitzero"`
+	Locations   []WishLocation `json:"locations,omitempty"`
+}
+
+func (w Wish) Fulfilled() bool {
+	return !w.FulfilledAt.IsZero()
+}
+
+// Matches returns the items the wish asks for.
+func (w Wish) Matches(items []Item) []Item {
+	if !w.Exact {
+		return FilterItems(items, FilterAll, FilterAll, FilterAll, w.Query, 0, maxWishLevel, FilterAll, FilterAll)
+	}
+	var matches []Item
+	for _, item := range items {
+		if strings.EqualFold(item.Name, w.Query) {
+			matches = append(matches, item)
+		}
+	}
+	return matches
+}
+
+// EvaluateWishes records where each wish is found in items, marking wishes
+// found for the first time as fulfilled at now; those are also returned.
+// Fulfilled wishes stay fulfilled with their last known locations when the
+// item is gone.
+func EvaluateWishes(wishes []Wish, items []Item, now time.Time) (updated, fulfilled []Wish) {
+	updated = make([]Wish, 0, len(wishes))
+	for _, wish := range wishes {
+		matches := wish.Matches(items)
+		if len(matches) > 0 {
+			wish.Locations = make([]WishLocation, 0, len(matches))
+			for _, item := range matches {
+				wish.Locations = append(wish.Locations, WishLocation{Identity: item.Identity(), Name: item.Name, Holder: item.CharacterName, Container: item.Container})
+			}
+			if !wish.Fulfilled() {
+				wish.FulfilledAt = now
+				fulfilled = append(fulfilled, wish)
+			}
+		}
+		updated = append(updated, wish)
+	}
+	return updated, fulfilled
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestEvaluateWishes(t *testing.T) {
+	added := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	now := added.Add(time.Hour)
+	wishes := []Wish{
+		{Query: "sword of shadow", Exact: true, Added: added},
+		{Query: "Raise Dead", Added: added},
+		{Query: "Sword", Exact: true, Added: added},
+	}
+	items := []Item{
+		{Name: "Sword of Shadow", Server: "Khyber", ItemID: 1, CharacterName: "CharB", Container: "Inventory", MinimumLevel: 12},
+		{Name: "Plain Wand", Server: "Khyber", ItemID: 2, CharacterName: "CharA", Container: "PersonalBank", Clicky: &Clicky{SpellName: "Raise Dead"}},
+	}
+
+	updated, fulfilled := EvaluateWishes(wishes, items, now)
+	assert.Equal(t, len(fulfilled), 2)
+	assert.Assert(t, updated[0].Fulfilled())
+	assert.DeepEqual(t, updated[0].Locations, []WishLocation{{Identity: "Khyber/1", Name: "Sword of Shadow", Holder: "CharB", Container: "Inventory"}})
+	assert.DeepEqual(t, updated[1].Locations, []WishLocation{{Identity: "Khyber/2", Name: "Plain Wand", Holder: "CharA", Container: "PersonalBank"}})
+	assert.Assert(t, !updated[2].Fulfilled())
+
+	// Already fulfilled wishes are not reported again and keep their
+	// locations once the item is gone.
+	later := now.Add(time.Hour)
+	updated, fulfilled = EvaluateWishes(updated, items[1:], later)
+	assert.Equal(t, len(fulfilled), 0)
+	assert.Equal(t, updated[0].FulfilledAt, now)
+	assert.Equal(t, updated[0].Locations[0].Holder, "CharB")
+}
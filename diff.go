@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fingon/ddo-trove-ui/db"
+)
+
+const (
+	diffFormatJSON     = "json"
+	diffFormatMarkdown = "markdown"
+)
+
+// DiffCmd compares two Trove snapshots, e.g. before and after a cleanup.
+type DiffCmd struct {
+	Before  string `arg:"" help:"Directory or archive with the earlier Trove JSON files." type:"path"`
+	After   string `arg:"" help:"Directory or archive with the later Trove JSON files." type:"path"`
+	Format  string `default:"table" enum:"table,json,markdown" help:"Output format: table, json or markdown." short:"f"`
+	Verbose bool   `env:"DDO_TROVE_VERBOSE" help:"Enable debug logging." short:"v"`
+}
+
+// diffEntry is an item in the JSON output.
+type diffEntry struct {
+	Name     string `json:"name"`
+	Identity string `json:"identity"`
+	Holder   string `json:"holder"`
+	Location string `json:"location"`
+	Quantity int    `json:"quantity"`
+}
+
+type diffChangeEntry struct {
+	Name     string           `json:"name"`
+	Identity string           `json:"identity"`
+	From     diffEntry        `json:"from"`
+	To       diffEntry        `json:"to"`
+	Changes  []db.FieldChange `json:"changes,omitempty"`
+}
+
+type diffOutput struct {
+	Gained  []diffEntry       `json:"gained"`
+	Lost    []diffEntry       `json:"lost"`
+	Moved   []diffChangeEntry `json:"moved"`
+	Changed []diffChangeEntry `json:"changed"`
+}
+
+func (c DiffCmd) Run() error {
+	configureLogging(c.Verbose)
+	return c.diff(os.Stdout)
+}
+
+func (c DiffCmd) diff(output io.Writer) error {
+	before, err := loadSnapshot(c.Before)
+	if err != nil {
+		return err
+	}
+	after, err := loadSnapshot(c.After)
+	if err != nil {
+		return err
+	}
+	diff := db.DiffItems(before, after)
+	switch c.Format {
+	case diffFormatJSON:
+		return writeDiffJSON(output, diff)
+	case diffFormatMarkdown:
+		return writeDiffMarkdown(output, diff)
+	default:
+		return writeDiffTable(output, diff)
+	}
+}
+
+// loadSnapshot loads one directory or archive; unlike the server, a missing
+// input is an error.
+func loadSnapshot(path string) ([]db.Item, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("open snapshot: %w", err)
+	}
+	allItems, err := loadItemsFromPath(path, info.IsDir())
+	if err != nil {
+		return nil, fmt.Errorf("load snapshot %q: %w", path, err)
+	}
+	return allItems.Items, nil
+}
+
+func newDiffEntry(item db.Item) diffEntry {
+	return diffEntry{
+		Name:     item.Name,
+		Identity: item.Identity(),
+		Holder:   item.CharacterName,
+		Location: itemLocation(item),
+		Quantity: item.Quantity,
+	}
+}
+
+func newDiffChangeEntry(change db.ItemChange) diffChangeEntry {
+	return diffChangeEntry{
+		Name:     change.After.Name,
+		Identity: change.After.Identity(),
+		From:     newDiffEntry(change.Before),
+		To:       newDiffEntry(change.After),
+		Changes:  change.Changes,
+	}
+}
+
+func writeDiffJSON(output io.Writer, diff db.TroveDiff) error {
+	result := diffOutput{
+		Gained:  make([]diffEntry, 0, len(diff.Gained)),
+		Lost:    make([]diffEntry, 0, len(diff.Lost)),
+		Moved:   make([]diffChangeEntry, 0, len(diff.Moved)),
+		Changed: make([]diffChangeEntry, 0, len(diff.Changed)),
+	}
+	for _, item := range diff.Gained {
+		result.Gained = append(result.Gained, newDiffEntry(item))
+	}
+	for _, item := range diff.Lost {
+		result.Lost = append(result.Lost, newDiffEntry(item))
+	}
+	for _, change := range diff.Moved {
+		result.Moved = append(result.Moved, newDiffChangeEntry(change))
+	}
+	for _, change := range diff.Changed {
+		result.Changed = append(result.Changed, newDiffChangeEntry(change))
+	}
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return fmt.Errorf("write JSON: %w", err)
+	}
+	return nil
+}
+
+// diffSection is one part of the table and Markdown output.
+type diffSection struct {
+	title  string
+	header []string
+	rows   [][]string
+}
+
+func diffSections(diff db.TroveDiff) []diffSection {
+	itemHeader := []string{"Item", "Holder", "Location", "Quantity"}
+	itemRows := func(items []db.Item) [][]string {
+		rows := make([][]string, 0, len(items))
+		for _, item := range items {
+			rows = append(rows, []string{item.Name, item.CharacterName, itemLocation(item), strconv.Itoa(item.Quantity)})
+		}
+		return rows
+	}
+	moved := diffSection{title: fmt.Sprintf("Moved (%d)", len(diff.Moved)), header: []string{"Item", "From", "To"}}
+	for _, change := range diff.Moved {
+		moved.rows = append(moved.rows, []string{change.After.Name, holderLocation(change.Before), holderLocation(change.After)})
+	}
+	changed := diffSection{title: fmt.Sprintf("Changed (%d)", len(diff.Changed)), header: []string{"Item", "Holder", "Change"}}
+	for _, change := range diff.Changed {
+		descriptions := make([]string, 0, len(change.Changes))
+		for _, field := range change.Changes {
+			descriptions = append(descriptions, fmt.Sprintf("%s %s -> %s", field.Field, emptyAsNone(field.Before), emptyAsNone(field.After)))
+		}
+		changed.rows = append(changed.rows, []string{change.After.Name, change.After.CharacterName, strings.Join(descriptions, "; ")})
+	}
+	return []diffSection{
+		{title: fmt.Sprintf("Gained (%d)", len(diff.Gained)), header: itemHeader, rows: itemRows(diff.Gained)},
+		{title: fmt.Sprintf("Lost (%d)", len(diff.Lost)), header: itemHeader, rows: itemRows(diff.Lost)},
+		moved,
+		changed,
+	}
+}
+
+func holderLocation(item db.Item) string {
+	return item.CharacterName + ": " + itemLocation(item)
+}
+
+func emptyAsNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+func writeDiffTable(output io.Writer, diff db.TroveDiff) error {
+	table := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	for index, section := range diffSections(diff) {
+		if index > 0 {
+			fmt.Fprintln(table)
+		}
+		fmt.Fprintln(table, strings.ToUpper(section.title))
+		if len(section.rows) == 0 {
+			continue
+		}
+		fmt.Fprintln(table, strings.ToUpper(strings.Join(section.header, "\t")))
+		for _, row := range section.rows {
+			fmt.Fprintln(table, strings.Join(row, "\t"))
+		}
+	}
+	if err := table.Flush(); err != nil {
+		return fmt.Errorf("write table: %w", err)
+	}
+	return nil
+}
+
+func writeDiffMarkdown(output io.Writer, diff db.TroveDiff) error {
+	var builder strings.Builder
+	for index, section := range diffSections(diff) {
+		if index > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString("## " + section.title + "\n")
+		if len(section.rows) == 0 {
+			continue
+		}
+		builder.WriteString("\n" + markdownRow(section.header))
+		builder.WriteString("|" + strings.Repeat(" --- |", len(section.header)) + "\n")
+		for _, row := range section.rows {
+			builder.WriteString(markdownRow(row))
+		}
+	}
+	if _, err := io.WriteString(output, builder.String()); err != nil {
+		return fmt.Errorf("write markdown: %w", err)
+	}
+	return nil
+}
+
+func markdownRow(cells []string) string {
+	escaped := make([]string, 0, len(cells))
+	for _, cell := range cells {
+		escaped = append(escaped, strings.ReplaceAll(cell, "|", `\|`))
+	}
+	return "| " + strings.Join(escaped, " | ") + " |\n"
+}
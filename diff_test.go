@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDiffCommand(t *testing.T) {
+	writeSnapshot := func(inventory string) string {
+		dir := t.TempDir()
+		data := `{"CharacterId": 1, "Name": "CharA", "Server": "Argonnessen", "Inventory": [` + inventory + `]}`
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, "char.json"), []byte(data), 0o600))
+		return dir
+	}
+	before := writeSnapshot(`
+		{"ItemId": 1, "Name": "Ring", "Container": "Inventory", "Row": 1, "Quantity": 1},
+		{"ItemId": 2, "Name": "Junk | Scrap", "Container": "Inventory", "Row": 2, "Quantity": 1},
+		{"ItemId": 3, "Name": "Wand", "Container": "Inventory", "Row": 3, "Quantity": 1, "Charges": 10}`)
+	after := writeSnapshot(`
+		{"ItemId": 1, "Name": "Ring", "Container": "Inventory", "Row": 5, "Quantity": 1},
+		{"ItemId": 3, "Name": "Wand", "Container": "Inventory", "Row": 3, "Quantity": 1, "Charges": 4},
+		{"ItemId": 4, "Name": "Tome", "Container": "Inventory", "Row": 2, "Quantity": 1}`)
+
+	run := func(args ...string) string {
+		cli, _, err := parseCLI(append([]string{"diff", before, after}, args...))
+		assert.NilError(t, err)
+		var output bytes.Buffer
+		assert.NilError(t, cli.Diff.diff(&output))
+		return output.String()
+	}
+
+	table := run()
+	for _, want := range []string{"GAINED (1)", "LOST (1)", "MOVED (1)", "CHANGED (1)", "charges 10 -> 4"} {
+		assert.Assert(t, strings.Contains(table, want), "missing %q in:\n%s", want, table)
+	}
+	assert.Assert(t, strings.Contains(strings.Join(strings.Fields(table), " "), "Ring CharA: Inventory, row 1 col 0 CharA: Inventory, row 5 col 0"))
+
+	markdown := run("--format", "markdown")
+	assert.Assert(t, strings.Contains(markdown, "## Lost (1)\n\n| Item | Holder | Location | Quantity |\n| --- | --- | --- | --- |\n| Junk \\| Scrap | CharA |"))
+
+	var result diffOutput
+	assert.NilError(t, json.Unmarshal([]byte(run("-f", "json")), &result))
+	assert.Equal(t, result.Gained[0].Name, "Tome")
+	assert.Equal(t, result.Gained[0].Identity, "Argonnessen/4")
+	assert.Equal(t, result.Moved[0].To.Location, "Inventory, row 5 col 0")
+	assert.Equal(t, result.Changed[0].Changes[0].Field, "charges")
+
+	_, _, err := parseCLI([]string{"diff", before, after, "--format", "xml"})
+	assert.Assert(t, err != nil)
+	cli, _, err := parseCLI([]string{"diff", before, filepath.Join(t.TempDir(), "missing")})
+	assert.NilError(t, err)
+	assert.ErrorContains(t, cli.Diff.diff(&bytes.Buffer{}), "open snapshot")
+}
@@ -9,6 +9,7 @@ require (
 
 require (
 	github.com/alecthomas/kong v1.14.0
+	go.yaml.in/yaml/v3 v3.0.4
 	gotest.tools/v3 v3.5.2
 )
 
@@ -205,7 +206,6 @@ require (
 	go.augendre.info/fatcontext v0.9.0 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
 	go.uber.org/zap v1.27.0 // indirect
-	go.yaml.in/yaml/v3 v3.0.4 // indirect
 	golang.org/x/exp/typeparams v0.0.0-20260209203927-2842357ff358 // indirect
 	golang.org/x/mod v0.33.0 // indirect
 	golang.org/x/sync v0.19.0 // indirect
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const ingredientsPath = "/ingredients"
+
+// handleIngredients sums ingredient and collectible stacks across holders.
+// Targets come in the query so that a shopping list can be bookmarked.
+func (a *App) handleIngredients(w http.ResponseWriter, r *http.Request) {
+	items, _ := a.visibleItemsAndHolders(r)
+	query := r.URL.Query()
+	search := strings.TrimSpace(query.Get("search"))
+	targetsText := query.Get("targets")
+
+	totals := db.AggregateIngredients(items)
+	status := http.StatusOK
+	message := ""
+	var targeted []db.IngredientTotal
+	targets, err := db.ParseIngredientTargets(targetsText)
+	if err != nil {
+		message = err.Error()
+		status = http.StatusBadRequest
+	} else {
+		targeted = db.ApplyTargets(totals, targets)
+	}
+	if search != "" {
+		searchLower := strings.ToLower(search)
+		var matching []db.IngredientTotal
+		for _, total := range totals {
+			if strings.Contains(strings.ToLower(total.Name), searchLower) {
+				matching = append(matching, total)
+			}
+		}
+		totals = matching
+	}
+
+	w.WriteHeader(status)
+	if err = templates.Ingredients(totals, targeted, search, targetsText, message).Render(w); err != nil {
+		slog.Error("render ingredients failed", "err", err)
+	}
+}
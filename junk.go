@@ -0,0 +1,41 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const (
+	junkPath              = "/junk"
+	defaultJunkBelowLevel = 10
+	defaultJunkOutgrownBy = 10
+)
+
+// handleJunk lists sale candidates by vendor value. Without a query the
+// default checks run, including duplicates.
+func (a *App) handleJunk(w http.ResponseWriter, r *http.Request) {
+	items, holders := a.visibleItemsAndHolders(r)
+	query := r.URL.Query()
+	options := db.JunkOptions{
+		BelowLevel: defaultJunkBelowLevel,
+		OutgrownBy: defaultJunkOutgrownBy,
+		Duplicates: len(query) == 0 || query.Get("duplicates") != "",
+		NonBTCOnly: query.Get("non_btc") != "",
+	}
+	if below, err := strconv.Atoi(query.Get("below")); err == nil && below >= 0 {
+		options.BelowLevel = below
+	}
+	if outgrownBy, err := strconv.Atoi(query.Get("outgrown_by")); err == nil && outgrownBy >= 0 {
+		options.OutgrownBy = outgrownBy
+	}
+
+	junk, totals := db.FindJunk(items, a.characterInfo(items, holders), options)
+	if err := templates.Junk(options, junk, totals).Render(w); err != nil {
+		slog.Error("render junk failed", "err", err)
+		http.Error(w, "failed to render junk report", http.StatusInternalServerError)
+	}
+}
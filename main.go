@@ -6,10 +6,10 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -19,31 +19,68 @@ import (
 )
 
 const (
-	itemsPerPage      = 100
-	defaultPage       = 1
-	defaultMinLevel   = 0
-	defaultMaxLevel   = 40
-	defaultPort       = 8080
-	defaultReload     = time.Minute
-	itemsPath         = "/items"
-	staticPathPrefix  = "/static/"
-	localhostTemplate = "http://localhost:%d"
+	itemsPerPage     = 100
+	defaultPage      = 1
+	defaultMinLevel  = 0
+	defaultMaxLevel  = 40
+	defaultPort      = 8080
+	defaultReload    = time.Minute
+	itemsPath        = "/items"
+	staticPathPrefix = "/static/"
 )
 
+type CLI struct {
+	Serve        Config          `cmd:"" default:"withargs" help:"Run the web server (default command)."`
+	HashPassword HashPasswordCmd `cmd:"" help:"Read a password from stdin and print its hash for the users file." name:"hash-password"`
+	Search       SearchCmd       `cmd:"" help:"Print items matching a search as a table or JSON."`
+	Report       ReportCmd       `cmd:"" help:"Write the trove as a static HTML site."`
+	Diff         DiffCmd         `cmd:"" help:"Show items gained, lost, moved and changed between two snapshots."`
+}
+
 type Config struct {
-	Port           int           `default:"8080" env:"DDO_TROVE_PORT" help:"HTTP port."`
-	ReloadInterval time.Duration `default:"1m" env:"DDO_TROVE_RELOAD_INTERVAL" help:"Polling interval for data reload." name:"reload-interval"`
-	Verbose        bool          `env:"DDO_TROVE_VERBOSE" help:"Enable debug logging." short:"v"`
-	Dirs           []string      `arg:"" help:"Input directories with Trove JSON files." name:"dirs"`
+	Port               int           `default:"8080" env:"DDO_TROVE_PORT" help:"HTTP port."`
+	Bind               string        `env:"DDO_TROVE_BIND" help:"Address to bind to; all interfaces if empty." name:"bind"`
+	TLSCert            string        `env:"DDO_TROVE_TLS_CERT" help:"TLS certificate file." name:"tls-cert" type:"existingfile"`
+	TLSKey             string        `env:"DDO_TROVE_TLS_KEY" help:"TLS private key file." name:"tls-key" type:"existingfile"`
+	TLSSelfSigned      bool          `env:"DDO_TROVE_TLS_SELF_SIGNED" help:"Serve TLS with an auto-generated self-signed certificate." name:"tls-self-signed"`
+	BasicAuthUser      string        `env:"DDO_TROVE_BASIC_AUTH_USER" help:"HTTP basic auth user name." name:"basic-auth-user"`
+	BasicAuthPass      string        `env:"DDO_TROVE_BASIC_AUTH_PASSWORD" help:"HTTP basic auth password." name:"basic-auth-password"`
+	BasicAuthFile      string        `env:"DDO_TROVE_BASIC_AUTH_FILE" help:"File with user:password lines (plain or hash-password output) for HTTP basic auth." name:"basic-auth-file" type:"existingfile"`
+	ReloadInterval     time.Duration `default:"1m" env:"DDO_TROVE_RELOAD_INTERVAL" help:"Polling interval for data reload." name:"reload-interval"`
+	Verbose            bool          `env:"DDO_TROVE_VERBOSE" help:"Enable debug logging." short:"v"`
+	UploadDir          string        `env:"DDO_TROVE_UPLOAD_DIR" help:"Managed directory for Trove JSON uploaded through the web UI." name:"upload-dir"`
+	UploadToken        string        `env:"DDO_TROVE_UPLOAD_TOKEN" help:"Token required for uploads." name:"upload-token"`
+	StaticDir          string        `env:"DDO_TROVE_STATIC_DIR" help:"Serve static files from this directory instead of the embedded copy (development)." name:"static-dir" type:"existingdir"`
+	StateDir           string        `env:"DDO_TROVE_STATE_DIR" help:"Directory for locally edited data such as reorganization rules (default: user config dir)." name:"state-dir"`
+	UsersFile          string        `env:"DDO_TROVE_USERS_FILE" help:"JSON file with user accounts; enables login and per-user visibility." name:"users-file"`
+	RecipesFile        string        `env:"DDO_TROVE_RECIPES_FILE" help:"JSON or YAML (.yaml/.yml) file with crafting recipes (default: recipes.json in the state directory)." name:"recipes-file"`
+	Webhooks           []string      `env:"DDO_TROVE_WEBHOOKS" help:"URL to POST a JSON summary of changes to after each reload; may be repeated." name:"webhook"`
+	WebhookFullPercent int           `default:"90" env:"DDO_TROVE_WEBHOOK_FULL_PERCENT" help:"Usage percentage at which webhooks report a bank or inventory as nearly full." name:"webhook-full-percent"`
+	Dirs               []string      `arg:"" help:"Input directories or .zip/.tar.gz/.tgz archives with Trove JSON files." name:"dirs" optional:""`
 }
 
 func (c Config) Validate() (err error) {
-	if len(c.Dirs) == 0 {
-		return errors.New("at least one input directory is required")
+	if len(c.Dirs) == 0 && c.UploadDir == "" {
+		return errors.New("at least one input directory or an upload directory is required")
+	}
+	if c.UploadDir != "" && c.UploadToken == "" {
+		return errors.New("upload directory requires an upload token")
 	}
 	if c.Port <= 0 || c.Port > 65535 {
 		return fmt.Errorf("port must be between 1 and 65535, got %d", c.Port)
 	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		return errors.New("TLS certificate and key must be given together")
+	}
+	if c.TLSCert != "" && c.TLSSelfSigned {
+		return errors.New("TLS certificate files and self-signed TLS are mutually exclusive")
+	}
+	if (c.BasicAuthUser == "") != (c.BasicAuthPass == "") {
+		return errors.New("basic auth user and password must be given together")
+	}
+	if c.WebhookFullPercent < 1 || c.WebhookFullPercent > 100 {
+		return fmt.Errorf("webhook full percent must be between 1 and 100, got %d", c.WebhookFullPercent)
+	}
 	if c.ReloadInterval <= 0 {
 		return fmt.Errorf("reload interval must be positive, got %s", c.ReloadInterval)
 	}
@@ -51,14 +88,18 @@ func (c Config) Validate() (err error) {
 }
 
 type FilterParams struct {
-	ItemType      string
-	ItemSubType   string
-	CharacterName string
-	NameSearch    string
-	EquipsTo      string
-	MinLevel      int
-	MaxLevel      int
-	Page          int
+	ItemType      string `json:"item_type"`
+	ItemSubType   string `json:"item_sub_type"`
+	CharacterName string `json:"character_name"`
+	NameSearch    string `json:"name_search"`
+	EquipsTo      string `json:"equips_to"`
+	SetName       string `json:"set_name"`
+	UsableBy      string `json:"usable_by"`
+	Tag           string `json:"tag"`
+	Favorites     bool   `json:"favorites"`
+	MinLevel      int    `json:"min_level"`
+	MaxLevel      int    `json:"max_level"`
+	Page          int    `json:"-"`
 }
 
 type PaginationResult struct {
@@ -69,8 +110,14 @@ type PaginationResult struct {
 }
 
 type App struct {
-	cfg Config
-
+	cfg      Config
+	users    *UserStore
+	webhooks *webhookNotifier
+	assets   *staticAssets
+	stateDir string
+	stateMu  sync.Mutex
+
+	reloadMu     sync.Mutex
 	mu           sync.RWMutex
 	allItems     *db.AllItems
 	fileModTimes map[string]time.Time
@@ -79,25 +126,23 @@ type App struct {
 	itemSubTypes   []string
 	characterNames []string
 	equipsToValues []string
+	setNames       []string
 }
 
-func parseConfig(args []string) (cfg Config, err error) {
+func parseCLI(args []string) (cli CLI, kctx *kong.Context, err error) {
 	parser, err := kong.New(
-		&cfg,
+		&cli,
 		kong.Name("ddo-trove-ui"),
 		kong.Description("Web UI for browsing DDO Trove item data."),
 		kong.UsageOnError(),
 	)
 	if err != nil {
-		return cfg, fmt.Errorf("create parser: %w", err)
-	}
-	if _, err = parser.Parse(args); err != nil {
-		return cfg, fmt.Errorf("parse arguments: %w", err)
+		return cli, nil, fmt.Errorf("create parser: %w", err)
 	}
-	if err = cfg.Validate(); err != nil {
-		return cfg, fmt.Errorf("invalid configuration: %w", err)
+	if kctx, err = parser.Parse(args); err != nil {
+		return cli, nil, fmt.Errorf("parse arguments: %w", err)
 	}
-	return cfg, nil
+	return cli, kctx, nil
 }
 
 func configureLogging(verbose bool) {
@@ -110,35 +155,64 @@ func configureLogging(verbose bool) {
 }
 
 func newApp(cfg Config) (app *App, err error) {
-	items, err := loadAndAggregateItems(cfg.Dirs)
-	if err != nil {
-		return nil, fmt.Errorf("initial load: %w", err)
+	if cfg.UploadDir != "" {
+		if err = os.MkdirAll(cfg.UploadDir, uploadDirPerm); err != nil {
+			return nil, fmt.Errorf("create upload directory: %w", err)
+		}
+	}
+
+	app = &App{cfg: cfg}
+	if app.stateDir, err = resolveStateDir(cfg.StateDir); err != nil {
+		return nil, err
+	}
+	if err = app.useStaticAssets(cfg.StaticDir); err != nil {
+		return nil, fmt.Errorf("load static assets: %w", err)
+	}
+	if cfg.UsersFile != "" {
+		if app.users, err = loadUserStore(cfg.UsersFile); err != nil {
+			return nil, fmt.Errorf("load users: %w", err)
+		}
 	}
 
-	modTimes := collectFileModTimes(cfg.Dirs)
+	if len(cfg.Webhooks) > 0 {
+		app.webhooks = newWebhookNotifier(cfg.Webhooks)
+	}
 
-	app = &App{
-		cfg:            cfg,
-		allItems:       items,
-		fileModTimes:   modTimes,
-		itemTypes:      db.GetUniqueItemTypes(items.Items),
-		itemSubTypes:   db.GetUniqueItemSubTypes(items.Items),
-		characterNames: db.GetUniqueCharacterNames(items.Items),
-		equipsToValues: db.GetUniqueEquipsTo(items.Items),
+	dirs := app.dataDirs()
+	items, err := loadAndAggregateItems(dirs)
+	if err != nil {
+		return nil, fmt.Errorf("initial load: %w", err)
 	}
 
-	slog.Info("initial load complete", "items", len(items.Items), "dirs", len(cfg.Dirs))
+	app.allItems = items
+	app.fileModTimes = collectFileModTimes(dirs)
+	app.itemTypes = db.GetUniqueItemTypes(items.Items)
+	app.itemSubTypes = db.GetUniqueItemSubTypes(items.Items)
+	app.characterNames = db.GetUniqueCharacterNames(items.Items)
+	app.equipsToValues = db.GetUniqueEquipsTo(items.Items)
+	app.setNames = db.GetUniqueSetNames(items.Items)
+
+	slog.Info("initial load complete", "items", len(items.Items), "dirs", len(dirs))
 	return app, nil
 }
 
 func (a *App) parseFilterParams(r *http.Request) FilterParams {
-	query := r.URL.Query()
+	return filterParamsFromValues(r.URL.Query())
+}
+
+// filterParamsFromValues reads filters from query or form values, filling
+// in defaults for missing or invalid ones.
+func filterParamsFromValues(query url.Values) FilterParams {
 	params := FilterParams{
 		ItemType:      query.Get("item_type"),
 		ItemSubType:   query.Get("item_sub_type"),
 		CharacterName: query.Get("character_name"),
 		NameSearch:    query.Get("name_search"),
 		EquipsTo:      query.Get("equips_to"),
+		SetName:       query.Get("set_name"),
+		UsableBy:      query.Get("usable_by"),
+		Tag:           query.Get("tag"),
+		Favorites:     query.Get("favorites") != "",
 		MinLevel:      defaultMinLevel,
 		MaxLevel:      defaultMaxLevel,
 		Page:          defaultPage,
@@ -156,6 +230,15 @@ func (a *App) parseFilterParams(r *http.Request) FilterParams {
 	if params.EquipsTo == "" {
 		params.EquipsTo = db.FilterAll
 	}
+	if params.SetName == "" {
+		params.SetName = db.FilterAll
+	}
+	if params.UsableBy == "" {
+		params.UsableBy = db.FilterAll
+	}
+	if params.Tag == "" {
+		params.Tag = db.FilterAll
+	}
 
 	if minLevelStr := query.Get("min_level"); minLevelStr != "" {
 		if minLevel, convErr := strconv.Atoi(minLevelStr); convErr == nil && minLevel >= 0 {
@@ -176,9 +259,43 @@ func (a *App) parseFilterParams(r *http.Request) FilterParams {
 	return params
 }
 
-func (a *App) applyFilterAndPaginate(items []db.Item, params FilterParams) PaginationResult {
-	filteredItems := db.FilterItems(
-		items,
+// values is the inverse of filterParamsFromValues, leaving out defaults.
+func (p FilterParams) values() url.Values {
+	values := url.Values{}
+	setUnlessDefault := func(key, value, defaultValue string) {
+		if value != "" && value != defaultValue {
+			values.Set(key, value)
+		}
+	}
+	setUnlessDefault("item_type", p.ItemType, db.FilterAll)
+	setUnlessDefault("item_sub_type", p.ItemSubType, db.FilterAll)
+	setUnlessDefault("character_name", p.CharacterName, db.FilterAll)
+	setUnlessDefault("name_search", p.NameSearch, "")
+	setUnlessDefault("equips_to", p.EquipsTo, db.FilterAll)
+	setUnlessDefault("set_name", p.SetName, db.FilterAll)
+	setUnlessDefault("usable_by", p.UsableBy, db.FilterAll)
+	setUnlessDefault("tag", p.Tag, db.FilterAll)
+	if p.Favorites {
+		values.Set("favorites", "1")
+	}
+	setUnlessDefault("min_level", strconv.Itoa(p.MinLevel), strconv.Itoa(defaultMinLevel))
+	setUnlessDefault("max_level", strconv.Itoa(p.MaxLevel), strconv.Itoa(defaultMaxLevel))
+	if p.Page > defaultPage {
+		values.Set("page", strconv.Itoa(p.Page))
+	}
+	return values
+}
+
+func (p FilterParams) indexPath() string {
+	if query := p.values().Encode(); query != "" {
+		return "/?" + query
+	}
+	return "/"
+}
+
+func filterItems(items []db.Item, params FilterParams) []db.Item {
+	return db.FilterItems(
+		db.FilterTagged(items, params.Tag, params.Favorites),
 		params.ItemType,
 		params.ItemSubType,
 		params.CharacterName,
@@ -186,7 +303,12 @@ func (a *App) applyFilterAndPaginate(items []db.Item, params FilterParams) Pagin
 		params.MinLevel,
 		params.MaxLevel,
 		params.EquipsTo,
+		params.SetName,
 	)
+}
+
+func (a *App) applyFilterAndPaginate(items []db.Item, params FilterParams) PaginationResult {
+	filteredItems := filterItems(items, params)
 
 	totalCount := len(filteredItems)
 	totalPages := (totalCount + itemsPerPage - 1) / itemsPerPage
@@ -230,31 +352,54 @@ func loadAndAggregateItems(dirPaths []string) (combinedAllItems *db.AllItems, er
 		if statErr != nil {
 			return nil, fmt.Errorf("stat input path %q: %w", absPath, statErr)
 		}
-		if !info.IsDir() {
-			slog.Warn("input path is not a directory, skipping", "path", absPath)
+		if !info.IsDir() && !db.IsArchive(absPath) {
+			slog.Warn("input path is neither a directory nor an archive, skipping", "path", absPath)
 			continue
 		}
 
-		dirItems, loadErr := db.LoadItemsFromDir(absPath)
+		dirItems, loadErr := loadItemsFromPath(absPath, info.IsDir())
 		if loadErr != nil {
-			slog.Error("failed loading items from directory", "path", absPath, "err", loadErr)
+			slog.Error("failed loading items", "path", absPath, "err", loadErr)
 			continue
 		}
+		for index := range dirItems.Items {
+			dirItems.Items[index].SourcePath = absPath
+		}
 		combinedAllItems.Items = append(combinedAllItems.Items, dirItems.Items...)
+		combinedAllItems.Holders = append(combinedAllItems.Holders, dirItems.Holders...)
 	}
 	return combinedAllItems, nil
 }
 
+func loadItemsFromPath(path string, isDir bool) (*db.AllItems, error) {
+	if isDir {
+		return db.LoadItemsFromDir(path)
+	}
+	return db.LoadItemsFromArchive(path)
+}
+
 func collectFileModTimes(dirPaths []string) map[string]time.Time {
 	currentFileModTimes := make(map[string]time.Time)
 	for _, dirPath := range dirPaths {
+		if db.IsArchive(dirPath) {
+			info, statErr := os.Stat(dirPath)
+			if statErr != nil {
+				slog.Warn("failed to stat archive while collecting mod times", "path", dirPath, "err", statErr)
+				continue
+			}
+			if !info.IsDir() {
+				currentFileModTimes[dirPath] = info.ModTime()
+				continue
+			}
+		}
+
 		files, err := os.ReadDir(dirPath)
 		if err != nil {
 			slog.Warn("failed to read directory while collecting mod times", "path", dirPath, "err", err)
 			continue
 		}
 		for _, file := range files {
-			if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			if file.IsDir() || !db.IsJSON(file.Name()) {
 				continue
 			}
 			filePath := filepath.Join(dirPath, file.Name())
@@ -289,7 +434,11 @@ func needsReload(oldTimes, newTimes map[string]time.Time) bool {
 }
 
 func (a *App) monitorAndReloadItems() {
-	newModTimes := collectFileModTimes(a.cfg.Dirs)
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+
+	dirs := a.dataDirs()
+	newModTimes := collectFileModTimes(dirs)
 
 	a.mu.RLock()
 	oldModTimes := make(map[string]time.Time, len(a.fileModTimes))
@@ -303,22 +452,26 @@ func (a *App) monitorAndReloadItems() {
 	}
 
 	slog.Info("detected data change, reloading")
-	newAllItems, err := loadAndAggregateItems(a.cfg.Dirs)
+	newAllItems, err := loadAndAggregateItems(dirs)
 	if err != nil {
 		slog.Error("failed to reload items", "err", err)
 		return
 	}
 
 	a.mu.Lock()
+	oldAllItems := a.allItems
 	a.allItems = newAllItems
 	a.fileModTimes = newModTimes
 	a.itemTypes = db.GetUniqueItemTypes(newAllItems.Items)
 	a.itemSubTypes = db.GetUniqueItemSubTypes(newAllItems.Items)
 	a.characterNames = db.GetUniqueCharacterNames(newAllItems.Items)
 	a.equipsToValues = db.GetUniqueEquipsTo(newAllItems.Items)
+	a.setNames = db.GetUniqueSetNames(newAllItems.Items)
 	a.mu.Unlock()
 
 	slog.Info("reload complete", "items", len(newAllItems.Items))
+	fulfilled := a.checkWishes(newAllItems.Items)
+	a.announceChanges(oldAllItems, newAllItems, fulfilled)
 }
 
 func (a *App) startMonitor(ctx context.Context) {
@@ -337,16 +490,38 @@ func (a *App) startMonitor(ctx context.Context) {
 }
 
 func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
+	items, holders := a.visibleItemsAndHolders(r)
 	a.mu.RLock()
-	items := a.allItems.Items
 	itemTypes := append([]string(nil), a.itemTypes...)
 	itemSubTypes := append([]string(nil), a.itemSubTypes...)
 	characterNames := append([]string(nil), a.characterNames...)
 	equipsToValues := append([]string(nil), a.equipsToValues...)
+	setNames := append([]string(nil), a.setNames...)
 	a.mu.RUnlock()
+	// The cached filter choices cover all items.
+	if scopedUser(r) != nil {
+		itemTypes = db.GetUniqueItemTypes(items)
+		itemSubTypes = db.GetUniqueItemSubTypes(items)
+		characterNames = db.GetUniqueCharacterNames(items)
+		equipsToValues = db.GetUniqueEquipsTo(items)
+		setNames = db.GetUniqueSetNames(items)
+	}
+	userName := ""
+	if user := userFromRequest(r); user != nil {
+		userName = user.Name
+	}
 
 	params := a.parseFilterParams(r)
-	result := a.applyFilterAndPaginate(items, params)
+	characters := a.characterInfo(items, holders)
+	usableByNames := make([]string, 0, len(characters))
+	for _, character := range characters {
+		usableByNames = append(usableByNames, character.Name)
+	}
+	result := a.applyFilterAndPaginate(filterUsableBy(items, characters, params.UsableBy), params)
+	searches, err := a.loadSearches(searchOwner(r))
+	if err != nil {
+		slog.Warn("load saved searches failed", "err", err)
+	}
 
 	slog.Info("render index",
 		"item_type", params.ItemType,
@@ -356,37 +531,44 @@ func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
 		"min_level", params.MinLevel,
 		"max_level", params.MaxLevel,
 		"equips_to", params.EquipsTo,
+		"set_name", params.SetName,
+		"usable_by", params.UsableBy,
+		"tag", params.Tag,
+		"favorites", params.Favorites,
 		"page", result.Page,
 		"count", result.TotalCount,
 	)
 
-	if err := templates.Index(
-		result.Items,
-		itemTypes,
-		params.ItemType,
-		itemSubTypes,
-		params.ItemSubType,
-		characterNames,
-		params.CharacterName,
-		params.MinLevel,
-		params.MaxLevel,
-		result.Page,
-		result.TotalPages,
-		result.TotalCount,
-		equipsToValues,
-		params.EquipsTo,
-	).Render(w); err != nil {
+	if err := templates.Index(templates.IndexView{
+		Items:      result.Items,
+		Page:       result.Page,
+		TotalPages: result.TotalPages,
+		TotalCount: result.TotalCount,
+		Params:     templates.IndexParams(params),
+		Options: templates.IndexOptions{
+			ItemTypes:      itemTypes,
+			ItemSubTypes:   itemSubTypes,
+			CharacterNames: characterNames,
+			EquipsTo:       equipsToValues,
+			SetNames:       setNames,
+			UsableBy:       usableByNames,
+			Tags:           db.GetUniqueTags(items),
+		},
+		SavedSearches: a.savedSearchViews(items, characters, searches),
+		UserName:      userName,
+	}).Render(w); err != nil {
 		slog.Error("render index failed", "err", err)
 		http.Error(w, "failed to render index", http.StatusInternalServerError)
 	}
 }
 
 func (a *App) handleItems(w http.ResponseWriter, r *http.Request) {
-	a.mu.RLock()
-	items := a.allItems.Items
-	a.mu.RUnlock()
+	items, holders := a.visibleItemsAndHolders(r)
 
 	params := a.parseFilterParams(r)
+	if params.UsableBy != db.FilterAll {
+		items = filterUsableBy(items, a.characterInfo(items, holders), params.UsableBy)
+	}
 	result := a.applyFilterAndPaginate(items, params)
 
 	slog.Debug("render items",
@@ -397,20 +579,19 @@ func (a *App) handleItems(w http.ResponseWriter, r *http.Request) {
 		"min_level", params.MinLevel,
 		"max_level", params.MaxLevel,
 		"equips_to", params.EquipsTo,
+		"set_name", params.SetName,
+		"usable_by", params.UsableBy,
+		"tag", params.Tag,
+		"favorites", params.Favorites,
 		"page", result.Page,
 		"count", result.TotalCount,
 	)
 
-	if err := templates.ItemList(
-		result.Items,
-		params.ItemType,
-		params.ItemSubType,
-		params.CharacterName,
-		result.Page,
-		result.TotalPages,
-		result.TotalCount,
-		params.EquipsTo,
-	).Render(w); err != nil {
+	// Keep the address bar in sync so reloads, history and shared links
+	// render the same view through handleIndex.
+	params.Page = result.Page
+	w.Header().Set("HX-Push-Url", params.indexPath())
+	if err := templates.ItemList(result.Items, result.Page, result.TotalPages, result.TotalCount).Render(w); err != nil {
 		slog.Error("render items failed", "err", err)
 		http.Error(w, "failed to render items", http.StatusInternalServerError)
 	}
@@ -418,20 +599,39 @@ func (a *App) handleItems(w http.ResponseWriter, r *http.Request) {
 
 func (a *App) routes() http.Handler {
 	mux := http.NewServeMux()
-	mux.Handle(staticPathPrefix, http.StripPrefix(staticPathPrefix, http.FileServer(http.Dir("static"))))
+	mux.Handle(staticPathPrefix, a.assets.handler())
 	mux.HandleFunc("/", a.handleIndex)
 	mux.HandleFunc(itemsPath, a.handleItems)
-	return mux
+	mux.HandleFunc(augmentsPath, a.handleAugments)
+	mux.HandleFunc(setsPath, a.handleSets)
+	mux.HandleFunc(clickiesPath, a.handleClickies)
+	mux.HandleFunc(ingredientsPath, a.handleIngredients)
+	mux.HandleFunc(recipesPath, a.handleRecipes)
+	mux.HandleFunc(wishListPath, a.handleWishList)
+	mux.HandleFunc(characterPath, a.handleCharacter)
+	mux.HandleFunc(profilesPath, a.handleProfiles)
+	mux.HandleFunc(transfersPath, a.handleTransfers)
+	mux.HandleFunc(upgradesPath, a.handleUpgrades)
+	mux.HandleFunc(reorgPath, a.handleReorg)
+	mux.HandleFunc(junkPath, a.handleJunk)
+	mux.HandleFunc(annotationsPath, a.handleAnnotations)
+	mux.HandleFunc(searchesPath, a.handleSearches)
+	mux.HandleFunc(searchesExportPath, a.handleSearchesExport)
+	mux.HandleFunc(searchesImportPath, a.handleSearchesImport)
+	if a.cfg.UploadDir != "" {
+		mux.HandleFunc(uploadPath, a.handleUpload)
+	}
+	if a.users == nil {
+		return mux
+	}
+	mux.HandleFunc(loginPath, a.handleLogin)
+	mux.HandleFunc(logoutPath, a.handleLogout)
+	return a.requireLogin(mux)
 }
 
-func run(args []string) (err error) {
-	cfg, err := parseConfig(args)
-	if err != nil {
-		return fmt.Errorf("parse config: %w", err)
-	}
-
-	configureLogging(cfg.Verbose)
-	app, err := newApp(cfg)
+func (c Config) Run() (err error) {
+	configureLogging(c.Verbose)
+	app, err := newApp(c)
 	if err != nil {
 		return fmt.Errorf("create app: %w", err)
 	}
@@ -440,16 +640,22 @@ func run(args []string) (err error) {
 	defer cancel()
 	app.startMonitor(ctx)
 
-	address := fmt.Sprintf(":%d", cfg.Port)
-	slog.Info("server starting", "url", fmt.Sprintf(localhostTemplate, cfg.Port), "address", address)
-
-	server := &http.Server{
-		Addr:    address,
-		Handler: app.routes(),
+	server, err := newServer(c, app.routes())
+	if err != nil {
+		return fmt.Errorf("create server: %w", err)
 	}
 
-	if err = server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		return fmt.Errorf("listen and serve: %w", err)
+	slog.Info("server starting", "url", c.displayURL(), "address", server.Addr)
+	return listenAndServe(c, server)
+}
+
+func run(args []string) (err error) {
+	_, kctx, err := parseCLI(args)
+	if err != nil {
+		return fmt.Errorf("parse config: %w", err)
+	}
+	if err = kctx.Run(); err != nil {
+		return fmt.Errorf("run %s: %w", kctx.Command(), err)
 	}
 	return nil
 }
@@ -1,7 +1,15 @@
 package main
 
 import (
+	"archive/zip"
+	"bytes"
+	"mime/multipart"
+	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -26,6 +34,9 @@ func TestParseFilterParams(t *testing.T) {
 				CharacterName: db.FilterAll,
 				NameSearch:    "",
 				EquipsTo:      db.FilterAll,
+				SetName:       db.FilterAll,
+				UsableBy:      db.FilterAll,
+				Tag:           db.FilterAll,
 				MinLevel:      defaultMinLevel,
 				MaxLevel:      defaultMaxLevel,
 				Page:          defaultPage,
@@ -33,13 +44,17 @@ func TestParseFilterParams(t *testing.T) {
 		},
 		{
 			name:  "all fields",
-			query: "?item_type=Weapon&item_sub_type=Sword&character_name=CharA&name_search=fire&equips_to=Hand&min_level=4&max_level=20&page=3",
+			query: "?item_type=Weapon&item_sub_type=Sword&character_name=CharA&name_search=fire&equips_to=Hand&set_name=Wayfarer&usable_by=CharA&tag=sell&favorites=1&min_level=4&max_level=20&page=3",
 			expected: FilterParams{
 				ItemType:      "Weapon",
 				ItemSubType:   "Sword",
 				CharacterName: "CharA",
 				NameSearch:    "fire",
 				EquipsTo:      "Hand",
+				SetName:       "Wayfarer",
+				UsableBy:      "CharA",
+				Tag:           "sell",
+				Favorites:     true,
 				MinLevel:      4,
 				MaxLevel:      20,
 				Page:          3,
@@ -54,6 +69,9 @@ func TestParseFilterParams(t *testing.T) {
 				CharacterName: db.FilterAll,
 				NameSearch:    "",
 				EquipsTo:      db.FilterAll,
+				SetName:       db.FilterAll,
+				UsableBy:      db.FilterAll,
+				Tag:           db.FilterAll,
 				MinLevel:      defaultMinLevel,
 				MaxLevel:      defaultMaxLevel,
 				Page:          defaultPage,
@@ -179,53 +197,113 @@ func TestNeedsReload(t *testing.T) {
 	}
 }
 
-func TestParseConfig(t *testing.T) {
+func TestLoadArchiveInput(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "trove.zip")
+
+	var zipData bytes.Buffer
+	zipWriter := zip.NewWriter(&zipData)
+	entry, err := zipWriter.Create("Trove/char.json")
+	assert.NilError(t, err)
+	_, err = entry.Write([]byte(`{"Name":"CharA","Inventory":[{"Name":"Sword","ItemType":"Weapon"}]}`))
+	assert.NilError(t, err)
+	assert.NilError(t, zipWriter.Close())
+	assert.NilError(t, os.WriteFile(archivePath, zipData.Bytes(), 0o600))
+
+	allItems, err := loadAndAggregateItems([]string{archivePath})
+	assert.NilError(t, err)
+	assert.Equal(t, len(allItems.Items), 1)
+
+	oldTimes := collectFileModTimes([]string{archivePath})
+	assert.Equal(t, len(oldTimes), 1)
+
+	later := time.Now().Add(time.Minute)
+	assert.NilError(t, os.Chtimes(archivePath, later, later))
+	assert.Assert(t, needsReload(oldTimes, collectFileModTimes([]string{archivePath})))
+}
+
+func TestParseCLI(t *testing.T) {
 	t.Run("valid args", func(t *testing.T) {
-		cfg, err := parseConfig([]string{"--port", "9090", "--reload-interval", "2m", "-v", "./data"})
+		cli, _, err := parseCLI([]string{"--port", "9090", "--reload-interval", "2m", "-v", "./data"})
 		assert.NilError(t, err)
-		assert.Equal(t, cfg.Port, 9090)
-		assert.Equal(t, cfg.ReloadInterval, 2*time.Minute)
-		assert.Equal(t, cfg.Verbose, true)
-		assert.DeepEqual(t, cfg.Dirs, []string{"./data"})
+		assert.Equal(t, cli.Serve.Port, 9090)
+		assert.Equal(t, cli.Serve.ReloadInterval, 2*time.Minute)
+		assert.Equal(t, cli.Serve.Verbose, true)
+		assert.DeepEqual(t, cli.Serve.Dirs, []string{"./data"})
 	})
 
 	t.Run("env overrides", func(t *testing.T) {
 		t.Setenv("DDO_TROVE_PORT", "7070")
 		t.Setenv("DDO_TROVE_RELOAD_INTERVAL", "3m")
 		t.Setenv("DDO_TROVE_VERBOSE", "true")
-		cfg, err := parseConfig([]string{"./data"})
+		cli, _, err := parseCLI([]string{"./data"})
 		assert.NilError(t, err)
-		assert.Equal(t, cfg.Port, 7070)
-		assert.Equal(t, cfg.ReloadInterval, 3*time.Minute)
-		assert.Equal(t, cfg.Verbose, true)
+		assert.Equal(t, cli.Serve.Port, 7070)
+		assert.Equal(t, cli.Serve.ReloadInterval, 3*time.Minute)
+		assert.Equal(t, cli.Serve.Verbose, true)
 	})
 
 	t.Run("missing directories", func(t *testing.T) {
-		_, err := parseConfig([]string{})
+		_, _, err := parseCLI([]string{})
+		assert.Assert(t, err != nil)
+	})
+
+	t.Run("upload directory without token", func(t *testing.T) {
+		_, _, err := parseCLI([]string{"--upload-dir", t.TempDir()})
+		assert.Assert(t, err != nil)
+	})
+
+	t.Run("tls key without certificate", func(t *testing.T) {
+		keyFile := filepath.Join(t.TempDir(), "key.pem")
+		assert.NilError(t, os.WriteFile(keyFile, []byte("key"), 0o600))
+		_, _, err := parseCLI([]string{"--tls-key", keyFile, "./data"})
+		assert.Assert(t, err != nil)
+	})
+
+	t.Run("basic auth user without password", func(t *testing.T) {
+		_, _, err := parseCLI([]string{"--basic-auth-user", "guild", "./data"})
 		assert.Assert(t, err != nil)
 	})
+
+	t.Run("upload directory only", func(t *testing.T) {
+		cli, _, err := parseCLI([]string{"--upload-dir", "./uploads", "--upload-token", "secret"})
+		assert.NilError(t, err)
+		assert.Equal(t, len(cli.Serve.Dirs), 0)
+	})
 }
 
-func TestRoutesAndHandlers(t *testing.T) {
-	items := []db.Item{{
-		Name:          "Flaming Sword",
-		ItemType:      "Weapon",
-		ItemSubType:   "Sword",
-		CharacterName: "CharA",
-		MinimumLevel:  5,
-		Quantity:      1,
-		EquipsTo:      []string{"Hand"},
-	}}
+func newTestApp(t *testing.T, items []db.Item) *App {
+	t.Helper()
 	app := &App{
 		cfg:            Config{Port: defaultPort, ReloadInterval: defaultReload, Dirs: []string{"."}},
+		stateDir:       t.TempDir(),
 		allItems:       &db.AllItems{Items: items},
 		fileModTimes:   map[string]time.Time{},
 		itemTypes:      db.GetUniqueItemTypes(items),
 		itemSubTypes:   db.GetUniqueItemSubTypes(items),
 		characterNames: db.GetUniqueCharacterNames(items),
 		equipsToValues: db.GetUniqueEquipsTo(items),
+		setNames:       db.GetUniqueSetNames(items),
 	}
+	assert.NilError(t, app.useStaticAssets(""))
+	return app
+}
 
+func TestRoutesAndHandlers(t *testing.T) {
+	items := []db.Item{{
+		Name:          "Flaming Sword",
+		ItemType:      "Weapon",
+		ItemSubType:   "Sword",
+		CharacterName: "CharA",
+		MinimumLevel:  5,
+		Quantity:      1,
+		EquipsTo:      []string{"Hand"},
+		Clicky:        &db.Clicky{SpellName: "Fireball", CasterLevel: 5, ValidTargets: []string{"Enemy"}},
+		Charges:       1,
+		MaxCharges:    10,
+	}}
+	app := newTestApp(t, items)
+	app.allItems.Holders = []db.Holder{{Name: "CharA", CharacterID: 1, Containers: []string{"Inventory"}}}
 	handler := app.routes()
 
 	t.Run("index route", func(t *testing.T) {
@@ -237,6 +315,183 @@ func TestRoutesAndHandlers(t *testing.T) {
 		assert.Assert(t, strings.Contains(recorder.Body.String(), "Flaming Sword"))
 	})
 
+	t.Run("character route", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest("GET", "/character?name=CharA", nil)
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, recorder.Code, 200)
+		assert.Assert(t, strings.Contains(recorder.Body.String(), "Flaming Sword"))
+	})
+
+	t.Run("sets route", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest("GET", "/sets?search=guard", nil)
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, recorder.Code, 200)
+		assert.Assert(t, strings.Contains(recorder.Body.String(), "Found 0 sets."))
+	})
+
+	t.Run("profiles route", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/items?usable_by=CharA", nil))
+		assert.Assert(t, strings.Contains(recorder.Body.String(), "Flaming Sword"))
+
+		form := url.Values{"name": {"CharA"}, "level": {"4"}, "classes": {"Wizard, "}}
+		recorder = httptest.NewRecorder()
+		request := httptest.NewRequest("POST", "/profiles", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, recorder.Code, 303)
+		assert.Equal(t, recorder.Header().Get("Location"), "/profiles?name=CharA")
+
+		profiles, err := app.loadProfiles()
+		assert.NilError(t, err)
+		assert.DeepEqual(t, profiles, []db.CharacterProfile{{CharacterID: 1, Name: "CharA", Level: 4, Classes: []string{"Wizard"}}})
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/profiles?name=CharA", nil))
+		assert.Equal(t, recorder.Code, 200)
+		assert.Assert(t, strings.Contains(recorder.Body.String(), `value="Wizard"`))
+
+		// The level 5 sword is now out of reach for the level 4 profile.
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/items?usable_by=CharA", nil))
+		assert.Assert(t, strings.Contains(recorder.Body.String(), "Found 0 items."))
+
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("POST", "/profiles", strings.NewReader(url.Values{"name": {"Nobody"}}.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, recorder.Code, 404)
+	})
+
+	t.Run("upgrades route", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/upgrades?level=4&levels=2&stats=Strength", nil))
+		assert.Equal(t, recorder.Code, 200)
+		body := recorder.Body.String()
+		assert.Assert(t, strings.Contains(body, "Items for levels 5 to 6 in 1 slots."))
+		assert.Assert(t, strings.Contains(body, "Flaming Sword"))
+
+		// The level comes from CharA's level 4 profile saved above; the level
+		// 5 sword is not usable yet but is within the window.
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/upgrades?character=CharA&levels=2", nil))
+		assert.Equal(t, recorder.Code, 200)
+		body = recorder.Body.String()
+		assert.Assert(t, strings.Contains(body, "Items for levels 5 to 6 in 1 slots."), body)
+		assert.Assert(t, strings.Contains(body, "Flaming Sword"))
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/upgrades", nil))
+		assert.Assert(t, strings.Contains(recorder.Body.String(), "Choose a character with a known level"))
+	})
+
+	t.Run("transfers route", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/transfers", nil))
+		assert.Equal(t, recorder.Code, 200)
+		assert.Assert(t, strings.Contains(recorder.Body.String(), "Found 0 suggestions."))
+	})
+
+	t.Run("reorg route", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest("POST", "/reorg", strings.NewReader(url.Values{"rules": {"type=Weapon"}}.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, recorder.Code, 400)
+		assert.Assert(t, strings.Contains(recorder.Body.String(), "line 1: missing"))
+
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("POST", "/reorg", strings.NewReader(url.Values{"rules": {"type=Weapon => Nobody"}}.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, recorder.Code, 303)
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/reorg", nil))
+		assert.Equal(t, recorder.Code, 200)
+		body := recorder.Body.String()
+		assert.Assert(t, strings.Contains(body, "type=Weapon =&gt; Nobody"))
+		assert.Assert(t, strings.Contains(body, "unknown holder Nobody on this server"))
+	})
+
+	t.Run("clickies route", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/clickies?spell=fire&sort=charges", nil))
+		assert.Equal(t, recorder.Code, 200)
+		body := recorder.Body.String()
+		assert.Assert(t, strings.Contains(body, "Found 1 spells."))
+		assert.Assert(t, strings.Contains(body, `<td class="charges-low">1/10</td>`))
+		assert.Assert(t, strings.Contains(body, "Fireball (CL 5), 1/10 charges"))
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/clickies?spell=heal", nil))
+		assert.Assert(t, strings.Contains(recorder.Body.String(), "Found 0 spells."))
+	})
+
+	t.Run("ingredients route", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		target := "/ingredients?" + url.Values{"targets": {"Cannith Essence: 10"}}.Encode()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", target, nil))
+		assert.Equal(t, recorder.Code, 200)
+		body := recorder.Body.String()
+		assert.Assert(t, strings.Contains(body, "1 of 1 ingredients short."))
+		assert.Assert(t, strings.Contains(body, "Found 0 ingredients."))
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/ingredients?targets=oops", nil))
+		assert.Equal(t, recorder.Code, http.StatusBadRequest)
+		assert.Assert(t, strings.Contains(recorder.Body.String(), "expected"))
+	})
+
+	t.Run("recipes route", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/recipes", nil))
+		assert.Equal(t, recorder.Code, 200)
+		assert.Assert(t, strings.Contains(recorder.Body.String(), "No recipes defined yet."))
+
+		recipes := `{"recipes": [{"name": "Essence Pile", "ingredients": [{"name": "Cannith Essence", "quantity": 5}]}]}`
+		assert.NilError(t, os.WriteFile(app.statePath(recipesStateFile), []byte(recipes), 0o600))
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/recipes", nil))
+		body := recorder.Body.String()
+		assert.Assert(t, strings.Contains(body, "0 of 1 recipes completable now."))
+		assert.Assert(t, strings.Contains(body, `<td class="shortfall">5</td>`))
+
+		assert.NilError(t, os.WriteFile(app.statePath(recipesStateFile), []byte(`{"recipes": [{"name": "Empty"}]}`), 0o600))
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/recipes", nil))
+		assert.Equal(t, recorder.Code, http.StatusBadRequest)
+		assert.Assert(t, strings.Contains(recorder.Body.String(), "has no ingredients"))
+	})
+
+	t.Run("junk route", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/junk", nil))
+		assert.Equal(t, recorder.Code, 200)
+		body := recorder.Body.String()
+		assert.Assert(t, strings.Contains(body, "Found 1 items worth 0cp."))
+		assert.Assert(t, strings.Contains(body, "<td>low level</td>"))
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/junk?below=5", nil))
+		assert.Assert(t, strings.Contains(recorder.Body.String(), "Found 0 items worth 0cp."))
+	})
+
+	t.Run("augments route", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest("GET", "/augments", nil)
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, recorder.Code, 200)
+		assert.Assert(t, strings.Contains(recorder.Body.String(), "Augment Slot Planner"))
+
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("GET", "/augments?key=Argonnessen/99", nil)
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, recorder.Code, 404)
+	})
+
 	t.Run("items route", func(t *testing.T) {
 		recorder := httptest.NewRecorder()
 		request := httptest.NewRequest("GET", "/items?item_type=Weapon&page=1", nil)
@@ -246,3 +501,174 @@ func TestRoutesAndHandlers(t *testing.T) {
 		assert.Assert(t, strings.Contains(recorder.Body.String(), "Flaming Sword"))
 	})
 }
+
+func TestAugmentsSelectByIdentity(t *testing.T) {
+	// Neither item has an ItemID, so only their identities tell them apart.
+	slot := []db.AugmentSlot{{Name: "Empty Blue Augment Slot", Color: "Blue"}}
+	items := []db.Item{
+		{Name: "Goggles", CharacterName: "CharA", OwnerID: 1, Server: "Argonnessen", AugmentSlots: slot},
+		{Name: "Bracers", CharacterName: "CharA", OwnerID: 1, Server: "Argonnessen", AugmentSlots: slot},
+	}
+	handler := newTestApp(t, items).routes()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/augments", nil))
+	body := recorder.Body.String()
+	query := url.Values{"key": {items[1].Identity()}}.Encode()
+	assert.Assert(t, strings.Contains(body, `href="/augments?`+query+`"`), body)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/augments?"+query, nil))
+	assert.Equal(t, recorder.Code, 200)
+	assert.Assert(t, strings.Contains(recorder.Body.String(), "<h2>Bracers (CharA, level 0)</h2>"))
+}
+
+func newUploadRequest(t *testing.T, source, token string, files map[string][]byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	assert.NilError(t, writer.WriteField(uploadSourceField, source))
+	assert.NilError(t, writer.WriteField(uploadTokenField, token))
+	for name, data := range files {
+		part, err := writer.CreateFormFile(uploadFormField, name)
+		assert.NilError(t, err)
+		_, err = part.Write(data)
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, writer.Close())
+
+	request := httptest.NewRequest(http.MethodPost, uploadPath, &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	return request
+}
+
+func TestUpload(t *testing.T) {
+	charJSON := []byte(`{"Name":"CharA","Inventory":[{"Name":"Sword","ItemType":"Weapon","MinimumLevel":1}]}`)
+
+	var zipData bytes.Buffer
+	zipWriter := zip.NewWriter(&zipData)
+	entry, err := zipWriter.Create("Trove/account.json")
+	assert.NilError(t, err)
+	_, err = entry.Write([]byte(`{"SharedBank":{"Tabs":{"0":{"Pages":{"0":{"Items":[{"Name":"Ring","ItemType":"Accessory"}]}}}}}}`))
+	assert.NilError(t, err)
+	assert.NilError(t, zipWriter.Close())
+
+	uploadDir := t.TempDir()
+	app, err := newApp(Config{ReloadInterval: defaultReload, UploadDir: uploadDir, UploadToken: "secret"})
+	assert.NilError(t, err)
+	handler := app.routes()
+
+	testCases := []struct {
+		name       string
+		source     string
+		token      string
+		files      map[string][]byte
+		expectCode int
+	}{
+		{
+			name:       "wrong token",
+			source:     "guildmate",
+			token:      "nope",
+			files:      map[string][]byte{"char.json": charJSON},
+			expectCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid source",
+			source:     "../evil",
+			token:      "secret",
+			files:      map[string][]byte{"char.json": charJSON},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid json",
+			source:     "guildmate",
+			token:      "secret",
+			files:      map[string][]byte{"char.json": charJSON, "bad.json": []byte(`{"invalid": true}`)},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "json and zip",
+			source:     "guildmate",
+			token:      "secret",
+			files:      map[string][]byte{"char.json": charJSON, "trove.zip": zipData.Bytes()},
+			expectCode: http.StatusOK,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, newUploadRequest(t, testCase.source, testCase.token, testCase.files))
+			assert.Equal(t, recorder.Code, testCase.expectCode)
+		})
+	}
+
+	stored, err := os.ReadDir(filepath.Join(uploadDir, "guildmate"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(stored), 2)
+	assert.DeepEqual(t, app.characterNames, []string{"Account (Shared Bank)", "CharA"})
+	assert.Equal(t, len(app.allItems.Items), 2)
+	// Upper case suffixes are accepted by the upload and must then be loaded.
+	recorder := httptest.NewRecorder()
+	charB := []byte(`{"Name":"CharB","Inventory":[{"Name":"Axe","ItemType":"Weapon"}]}`)
+	handler.ServeHTTP(recorder, newUploadRequest(t, "casefolk", "secret", map[string][]byte{"Char.JSON": charB}))
+	assert.Equal(t, recorder.Code, http.StatusOK)
+	_, err = os.Stat(filepath.Join(uploadDir, "casefolk", "Char.JSON"))
+	assert.NilError(t, err)
+	app.fileModTimes = map[string]time.Time{}
+	app.monitorAndReloadItems()
+	assert.DeepEqual(t, app.characterNames, []string{"Account (Shared Bank)", "CharA", "CharB"})
+	assert.Equal(t, len(app.allItems.Items), 3)
+}
+
+func TestURLStateRoundTrip(t *testing.T) {
+	var items []db.Item
+	for index := range itemsPerPage + 20 {
+		items = append(items, db.Item{
+			Name:          "Fire Blade " + strconv.Itoa(index),
+			ItemType:      "Weapon",
+			ItemSubType:   "Sword",
+			CharacterName: "CharA",
+			MinimumLevel:  10,
+			EquipsTo:      []string{"Hand"},
+		})
+	}
+	items = append(items, db.Item{Name: "Ring", ItemType: "Jewelry", CharacterName: "CharB", MinimumLevel: 10})
+	handler := newTestApp(t, items).routes()
+
+	query := "?character_name=CharA&equips_to=Hand&item_sub_type=Sword&item_type=Weapon&max_level=20&min_level=5&name_search=fire&page=2"
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/items"+query, nil)
+	request.Header.Set("HX-Request", "true")
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, recorder.Code, 200)
+	pushed := recorder.Header().Get("HX-Push-Url")
+	assert.Equal(t, pushed, "/"+query)
+
+	// Out of range pages fall back to the first page in the pushed URL.
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/items?item_type=Jewelry&page=9", nil))
+	assert.Equal(t, recorder.Header().Get("HX-Push-Url"), "/?item_type=Jewelry")
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", pushed, nil))
+	assert.Equal(t, recorder.Code, 200)
+	body := recorder.Body.String()
+	for _, want := range []string{
+		`<option value="Weapon" selected>`,
+		`<option value="Sword" selected>`,
+		`<option value="CharA" selected>`,
+		`<option value="Hand" selected>`,
+		`name="name_search" value="fire"`,
+		`name="min_level" value="5"`,
+		`name="max_level" value="20"`,
+		`class="active pagination-button" data-hx-get="/items?page=2"`,
+		"Found 120 items.",
+		"Fire Blade 99",
+	} {
+		assert.Assert(t, strings.Contains(body, want), "missing %q", want)
+	}
+	assert.Assert(t, !strings.Contains(body, "Ring"))
+	// Pagination sends every filter along with the page.
+	assert.Assert(t, strings.Contains(body, `data-hx-include="#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #nameSearch, #minLevel, #maxLevel, #equipsToFilter, #setFilter, #usableByFilter, #tagFilter, #favoritesFilter"`))
+}
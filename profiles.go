@@ -0,0 +1,162 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const (
+	profilesPath      = "/profiles"
+	profilesStateFile = "character-profiles.json"
+)
+
+type profilesState struct {
+	Profiles []db.CharacterProfile `json:"profiles"`
+}
+
+func (a *App) loadProfiles() (profiles []db.CharacterProfile, err error) {
+	var state profilesState
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+	if err = loadState(a.statePath(profilesStateFile), &state); err != nil {
+		return nil, err
+	}
+	return state.Profiles, nil
+}
+
+// saveProfile replaces the stored profile matching character, or adds it.
+func (a *App) saveProfile(character db.CharacterInfo, profile db.CharacterProfile) error {
+	path := a.statePath(profilesStateFile)
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+	var state profilesState
+	if err := loadState(path, &state); err != nil {
+		return err
+	}
+	replaced := false
+	for index, existing := range state.Profiles {
+		if existing.Matches(character) {
+			state.Profiles[index] = profile
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		state.Profiles = append(state.Profiles, profile)
+	}
+	return saveState(path, state)
+}
+
+// characterInfo returns what is known about the characters among items:
+// inferred from equipped gear, overridden by saved profiles.
+func (a *App) characterInfo(items []db.Item, holders []db.Holder) []db.CharacterInfo {
+	characters := db.InferCharacterInfo(items, holders)
+	profiles, err := a.loadProfiles()
+	if err != nil {
+		slog.Warn("load character profiles failed", "err", err)
+		return characters
+	}
+	return db.ApplyProfiles(characters, profiles)
+}
+
+func findCharacter(characters []db.CharacterInfo, name string) (character db.CharacterInfo, found bool) {
+	for _, character = range characters {
+		if character.Name == name {
+			return character, true
+		}
+	}
+	return character, false
+}
+
+func (a *App) handleProfiles(w http.ResponseWriter, r *http.Request) {
+	items, holders := a.visibleItemsAndHolders(r)
+	inferred := db.InferCharacterInfo(items, holders)
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		name := r.PostFormValue("name")
+		character, found := findCharacter(inferred, name)
+		if !found {
+			http.Error(w, "unknown character", http.StatusNotFound)
+			return
+		}
+		profile := profileFromForm(r, character)
+		if err := a.saveProfile(character, profile); err != nil {
+			slog.Error("save character profile failed", "err", err)
+			http.Error(w, "failed to save profile", http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, profilesPath+"?"+url.Values{"name": {name}}.Encode(), http.StatusSeeOther)
+		return
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	profiles, err := a.loadProfiles()
+	if err != nil {
+		slog.Error("load character profiles failed", "err", err)
+		http.Error(w, "failed to load profiles", http.StatusInternalServerError)
+		return
+	}
+	selected := r.URL.Query().Get("name")
+	var profile *db.CharacterProfile
+	var selectedInferred db.CharacterInfo
+	if character, found := findCharacter(inferred, selected); found {
+		stored, _ := db.FindProfile(profiles, character)
+		profile = &stored
+		selectedInferred = character
+	}
+
+	if err = templates.Profiles(db.ApplyProfiles(inferred, profiles), selected, profile, selectedInferred).Render(w); err != nil {
+		slog.Error("render profiles failed", "err", err)
+		http.Error(w, "failed to render profiles", http.StatusInternalServerError)
+	}
+}
+
+func profileFromForm(r *http.Request, character db.CharacterInfo) db.CharacterProfile {
+	profile := db.CharacterProfile{
+		CharacterID:   character.CharacterID,
+		Name:          character.Name,
+		Race:          strings.TrimSpace(r.PostFormValue("race")),
+		Classes:       splitList(r.PostFormValue("classes")),
+		Proficiencies: splitList(r.PostFormValue("proficiencies")),
+		WeaponTypes:   splitList(r.PostFormValue("weapon_types")),
+		ArmorTypes:    splitList(r.PostFormValue("armor_types")),
+	}
+	if level, err := strconv.Atoi(strings.TrimSpace(r.PostFormValue("level"))); err == nil && level > 0 {
+		profile.Level = level
+	}
+	return profile
+}
+
+// splitList reads a comma separated form value, dropping empty entries.
+func splitList(value string) []string {
+	var values []string
+	for part := range strings.SplitSeq(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// filterUsableBy narrows items to those the named character could use; an
+// unknown name matches nothing.
+func filterUsableBy(items []db.Item, characters []db.CharacterInfo, name string) []db.Item {
+	if name == "" || name == db.FilterAll {
+		return items
+	}
+	character, found := findCharacter(characters, name)
+	if !found {
+		return nil
+	}
+	return db.FilterUsableBy(items, character)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	recipesPath      = "/recipes"
+	recipesStateFile = "recipes.json"
+)
+
+type recipesFile struct {
+	Recipes []db.Recipe `json:"recipes" yaml:"recipes"`
+}
+
+// recipesFilePath is the hand-edited recipes file; it is read on every
+// request so that edits show up without a restart.
+func (a *App) recipesFilePath() string {
+	if a.cfg.RecipesFile != "" {
+		return a.cfg.RecipesFile
+	}
+	return a.statePath(recipesStateFile)
+}
+
+// loadRecipes reads a JSON recipes file, or YAML when the file name ends in
+// .yaml or .yml. A missing file means no recipes.
+func loadRecipes(path string) ([]db.Recipe, error) {
+	var file recipesFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read recipes %q: %w", path, err)
+		}
+		if err = yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("parse recipes %q: %w", path, err)
+		}
+	default:
+		if err := loadState(path, &file); err != nil {
+			return nil, err
+		}
+	}
+	for index, recipe := range file.Recipes {
+		if err := recipe.Validate(); err != nil {
+			return nil, fmt.Errorf("recipe %d in %q: %w", index+1, path, err)
+		}
+	}
+	return file.Recipes, nil
+}
+
+func (a *App) handleRecipes(w http.ResponseWriter, r *http.Request) {
+	items, _ := a.visibleItemsAndHolders(r)
+	path := a.recipesFilePath()
+	status := http.StatusOK
+	message := ""
+	var checks []db.RecipeCheck
+	recipes, err := loadRecipes(path)
+	if err != nil {
+		// A broken recipes file is a configuration error the user has to fix.
+		slog.Warn("load recipes failed", "err", err)
+		message = err.Error()
+		status = http.StatusBadRequest
+	} else {
+		checks = db.CheckRecipes(recipes, db.AggregateIngredients(items))
+	}
+
+	w.WriteHeader(status)
+	if err = templates.Recipes(path, message, checks).Render(w); err != nil {
+		slog.Error("render recipes failed", "err", err)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLoadRecipesYAML(t *testing.T) {
+	recipes := `recipes:
+  - name: Tier 1
+    group: Green Steel
+    ingredients:
+      - name: Shroud Gem
+        quantity: 2
+`
+	for _, name := range []string{"recipes.yaml", "recipes.YML"} {
+		path := filepath.Join(t.TempDir(), name)
+		assert.NilError(t, os.WriteFile(path, []byte(recipes), 0o600))
+		loaded, err := loadRecipes(path)
+		assert.NilError(t, err, name)
+		assert.Equal(t, len(loaded), 1, name)
+		assert.Equal(t, loaded[0].Group, "Green Steel")
+		assert.Equal(t, loaded[0].Ingredients[0].Name, "Shroud Gem")
+		assert.Equal(t, loaded[0].Ingredients[0].Quantity, 2)
+	}
+
+	path := filepath.Join(t.TempDir(), "broken.yml")
+	assert.NilError(t, os.WriteFile(path, []byte("recipes: [\n"), 0o600))
+	_, err := loadRecipes(path)
+	assert.ErrorContains(t, err, "parse recipes")
+
+	loaded, err := loadRecipes(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(loaded), 0)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const (
+	reorgPath      = "/reorg"
+	reorgStateFile = "reorg-rules.json"
+)
+
+// reorgState keeps each user's rules; without user accounts everything is
+// stored under the empty name.
+type reorgState struct {
+	Users map[string]string `json:"users"`
+}
+
+func (a *App) loadReorgRules(owner string) (rules string, err error) {
+	var state reorgState
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+	if err = loadState(a.statePath(reorgStateFile), &state); err != nil {
+		return "", err
+	}
+	return state.Users[owner], nil
+}
+
+func (a *App) saveReorgRules(owner, rules string) error {
+	path := a.statePath(reorgStateFile)
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+	var state reorgState
+	if err := loadState(path, &state); err != nil {
+		return err
+	}
+	if state.Users == nil {
+		state.Users = make(map[string]string)
+	}
+	state.Users[owner] = rules
+	return saveState(path, state)
+}
+
+func (a *App) handleReorg(w http.ResponseWriter, r *http.Request) {
+	owner := searchOwner(r)
+	switch r.Method {
+	case http.MethodGet:
+		rules, err := a.loadReorgRules(owner)
+		if err != nil {
+			slog.Error("load reorganization rules failed", "err", err)
+			http.Error(w, "failed to load rules", http.StatusInternalServerError)
+			return
+		}
+		a.renderReorg(w, r, rules, http.StatusOK)
+	case http.MethodPost:
+		rules := r.PostFormValue("rules")
+		if _, err := db.ParseMoveRules(rules); err != nil {
+			a.renderReorg(w, r, rules, http.StatusBadRequest)
+			return
+		}
+		if err := a.saveReorgRules(owner, rules); err != nil {
+			slog.Error("save reorganization rules failed", "err", err)
+			http.Error(w, "failed to save rules", http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, reorgPath, http.StatusSeeOther)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *App) renderReorg(w http.ResponseWriter, r *http.Request, rulesText string, status int) {
+	items, holders := a.visibleItemsAndHolders(r)
+
+	var moves []db.Move
+	var skipped []db.SkippedMove
+	message := ""
+	rules, err := db.ParseMoveRules(rulesText)
+	if err != nil {
+		message = err.Error()
+	} else {
+		moves, skipped = db.PlanMoves(items, holders, rules)
+	}
+
+	w.WriteHeader(status)
+	if err = templates.Reorg(rulesText, message, moves, skipped).Render(w); err != nil {
+		slog.Error("render reorganization failed", "err", err)
+	}
+}
+
+// holdersOf keeps the holders that own at least one of items.
+func holdersOf(items []db.Item, holders []db.Holder) []db.Holder {
+	names := make(map[string]bool)
+	for _, item := range items {
+		names[item.CharacterName] = true
+	}
+	var kept []db.Holder
+	for _, holder := range holders {
+		if names[holder.Name] {
+			kept = append(kept, holder)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestReorgRulesPerUser(t *testing.T) {
+	app := newTestApp(t, nil)
+	rules, err := app.loadReorgRules("")
+	assert.NilError(t, err)
+	assert.Equal(t, rules, "")
+
+	assert.NilError(t, app.saveReorgRules("", "* => owner"))
+	assert.NilError(t, app.saveReorgRules("alice", "type=Augment => Alice"))
+	assert.NilError(t, app.saveReorgRules("bob", "type=Augment => Bob"))
+	for owner, want := range map[string]string{"": "* => owner", "alice": "type=Augment => Alice", "bob": "type=Augment => Bob", "carol": ""} {
+		rules, err = app.loadReorgRules(owner)
+		assert.NilError(t, err)
+		assert.Equal(t, rules, want, owner)
+	}
+}
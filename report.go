@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+	g "maragu.dev/gomponents"
+)
+
+const (
+	reportDirPerm  = 0o755
+	reportFilePerm = 0o644
+)
+
+// ReportCmd writes the trove as a static site that needs no server.
+type ReportCmd struct {
+	Output string     `arg:"" help:"Directory to write the site to; existing pages are overwritten." type:"path"`
+	Inputs InputFlags `embed:""`
+}
+
+// reportSearchEntry is one item of search-index.js. Text holds everything
+// the live full text search looks at, lowercased.
+type reportSearchEntry struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Level  int    `json:"level"`
+	Holder string `json:"holder"`
+	URL    string `json:"url"`
+	Text   string `json:"text"`
+}
+
+func (c ReportCmd) Validate() error {
+	return c.Inputs.validate()
+}
+
+func (c ReportCmd) Run() error {
+	allItems, err := c.Inputs.load()
+	if err != nil {
+		return err
+	}
+	if err = writeReport(c.Output, allItems, time.Now()); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d items to %s\n", len(allItems.Items), filepath.Join(c.Output, templates.ReportIndexPage))
+	return nil
+}
+
+func writeReport(outputDir string, allItems *db.AllItems, generated time.Time) error {
+	items := append([]db.Item(nil), allItems.Items...)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+
+	style, err := fs.ReadFile(embeddedStatic, staticDirName+"/"+templates.ReportStyleSheet)
+	if err != nil {
+		return fmt.Errorf("read style sheet: %w", err)
+	}
+	if err = writeReportFile(outputDir, templates.ReportStyleSheet, style); err != nil {
+		return err
+	}
+
+	// Characters of the same name on different servers are different
+	// holders. Page names are assigned to characters first, then to items,
+	// both in a stable order, so that they do not change between runs.
+	type holderKey struct{ server, name string }
+	var keys []holderKey
+	held := make(map[holderKey][]db.Item)
+	for _, item := range items {
+		key := holderKey{server: item.Server, name: item.CharacterName}
+		if _, exists := held[key]; !exists {
+			keys = append(keys, key)
+		}
+		held[key] = append(held[key], item)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].server < keys[j].server
+	})
+	paths := templates.NewReportPaths()
+	for _, key := range keys {
+		paths.Character(key.server, key.name)
+	}
+	for _, item := range items {
+		paths.Item(item)
+	}
+
+	holders := make([]templates.ReportHolder, 0, len(keys))
+	for _, key := range keys {
+		holders = append(holders, templates.ReportHolder{Name: key.name, Server: key.server, ItemCount: len(held[key])})
+		if err = writeReportPage(outputDir, paths.Character(key.server, key.name), templates.ReportCharacter(key.name, held[key], paths)); err != nil {
+			return err
+		}
+	}
+	if err = writeReportPage(outputDir, templates.ReportCharactersPage, templates.ReportCharacters(holders, paths)); err != nil {
+		return err
+	}
+
+	index := db.NewItemIndex(items)
+	entries := make([]reportSearchEntry, 0, len(index))
+	written := make(map[string]bool, len(index))
+	for _, item := range items {
+		identity := item.Identity()
+		if written[identity] {
+			continue
+		}
+		written[identity] = true
+		path := paths.Item(item)
+		if err = writeReportPage(outputDir, path, templates.ReportItem(index[identity], paths)); err != nil {
+			return err
+		}
+		entries = append(entries, reportSearchEntry{
+			Name:   item.Name,
+			Type:   item.ItemType,
+			Level:  item.MinimumLevel,
+			Holder: item.CharacterName,
+			URL:    path,
+			Text:   searchText(item),
+		})
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("encode search index: %w", err)
+	}
+	// A script rather than JSON, as browsers do not let pages opened from
+	// disk fetch files next to them.
+	script := append([]byte("window.troveSearchIndex = "), data...)
+	if err = writeReportFile(outputDir, templates.ReportSearchIndexFile, append(script, ";\n"...)); err != nil {
+		return err
+	}
+
+	return writeReportPage(outputDir, templates.ReportIndexPage, templates.ReportIndex(len(items), generated.Format(time.DateTime)))
+}
+
+// searchText gathers the fields db.FilterItems searches.
+func searchText(item db.Item) string {
+	parts := []string{item.Name, item.Description, item.SetBonus1Name}
+	for _, effect := range item.Effects {
+		parts = append(parts, effect.Name, effect.Description)
+	}
+	if item.Clicky != nil {
+		parts = append(parts, item.Clicky.SpellName, item.Clicky.SpellDescription)
+	}
+	parts = append(parts, item.SetBonus1Description...)
+	return strings.ToLower(strings.Join(parts, "\n"))
+}
+
+func writeReportPage(outputDir, name string, page g.Node) error {
+	var buffer bytes.Buffer
+	if err := page.Render(&buffer); err != nil {
+		return fmt.Errorf("render %s: %w", name, err)
+	}
+	return writeReportFile(outputDir, name, buffer.Bytes())
+}
+
+func writeReportFile(outputDir, name string, data []byte) error {
+	path := filepath.Join(outputDir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), reportDirPerm); err != nil {
+		return fmt.Errorf("create directory for %s: %w", name, err)
+	}
+	if err := os.WriteFile(path, data, reportFilePerm); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"gotest.tools/v3/assert"
+)
+
+func TestWriteReport(t *testing.T) {
+	allItems := &db.AllItems{
+		Items: []db.Item{
+			{ItemID: 7, Name: "Fire Blade", ItemType: "Weapon", CharacterName: "Foo Bar", Server: "Argonnessen", Container: "Inventory", Quantity: 1,
+				Effects: []db.Effect{{Name: "Flaming", Description: "Deals fire damage"}}},
+			{Name: "Potion", ItemType: "Potion", CharacterName: "Foo Bar", Server: "Argonnessen", Container: "Inventory", Quantity: 5},
+			{Name: "Potion", ItemType: "Potion", CharacterName: "Account (Shared Bank)", Server: "Argonnessen", Container: "SharedBank", Quantity: 20},
+			{ItemID: 8, Name: "Ring", CharacterName: "Foo_Bar", Server: "Argonnessen", Container: "Inventory", Quantity: 1},
+			{ItemID: 9, Name: "Ring", CharacterName: "Foo.Bar", Server: "Argonnessen", Container: "Inventory", Quantity: 1},
+			{ItemID: 10, Name: "Ring", CharacterName: "Foo Bar", Server: "Khyber", Container: "Inventory", Quantity: 1},
+		},
+		Holders: []db.Holder{{Name: "Foo Bar", CharacterID: 1, Server: "Argonnessen"}},
+	}
+	dir := t.TempDir()
+	assert.NilError(t, writeReport(dir, allItems, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		assert.NilError(t, err)
+		return string(data)
+	}
+
+	index := read("index.html")
+	assert.Assert(t, strings.Contains(index, "6 items, generated 2026-01-02 03:04:05."))
+	assert.Assert(t, strings.Contains(index, `<script src="search-index.js"></script>`))
+	characters := read("characters.html")
+	assert.Assert(t, strings.Contains(characters, `<a href="characters/Argonnessen_Foo_Bar.html">Foo Bar</a></td><td>Argonnessen</td><td>2</td>`))
+	assert.Assert(t, strings.Contains(read("characters/Argonnessen_Foo_Bar.html"), `<a href="../items/Argonnessen_7.html">Fire Blade</a>`))
+	// Names that share a slug, and the same name on another server, each
+	// get their own page.
+	assert.Assert(t, strings.Contains(characters, `<a href="characters/Khyber_Foo_Bar.html">Foo Bar</a></td><td>Khyber</td><td>1</td>`))
+	assert.Assert(t, strings.Contains(characters, `<a href="characters/Argonnessen_Foo_Bar-2.html">Foo.Bar</a>`))
+	assert.Assert(t, strings.Contains(characters, `<a href="characters/Argonnessen_Foo_Bar-3.html">Foo_Bar</a>`))
+	assert.Assert(t, strings.Contains(read("characters/Argonnessen_Foo_Bar-3.html"), `<a href="../items/Argonnessen_8.html">Ring</a>`))
+	assert.Assert(t, strings.Contains(read("items/Argonnessen_7.html"), `href="../style.css"`))
+	assert.Assert(t, strings.Contains(read("style.css"), ".item-tooltip"))
+
+	// The two potions are identical copies and share a page.
+	potion := db.Item{Name: "Potion", Server: "Argonnessen"}
+	potionPage := read("items/Argonnessen_fp-" + db.Fingerprint(potion) + ".html")
+	assert.Assert(t, strings.Contains(potionPage, "Held by"))
+	assert.Assert(t, strings.Contains(potionPage, "SharedBank, quantity 20"))
+
+	script := read("search-index.js")
+	assert.Assert(t, strings.HasPrefix(script, "window.troveSearchIndex = "))
+	var entries []reportSearchEntry
+	assert.NilError(t, json.Unmarshal([]byte(strings.TrimSuffix(strings.TrimPrefix(script, "window.troveSearchIndex = "), ";\n")), &entries))
+	assert.Equal(t, len(entries), 5)
+	assert.Equal(t, entries[0].URL, "items/Argonnessen_7.html")
+	assert.Assert(t, strings.Contains(entries[0].Text, "deals fire damage"))
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fingon/ddo-trove-ui/db"
+)
+
+// SearchCmd prints the items matching a query without starting the server.
+type SearchCmd struct {
+	Query    string     `arg:"" help:"Full text search over names, effects, descriptions, clickies and sets; empty matches everything." optional:""`
+	Type     string     `help:"Item type." name:"type"`
+	SubType  string     `help:"Item sub type." name:"sub-type"`
+	Char     string     `help:"Character or bank holding the item." name:"char"`
+	EquipsTo string     `help:"Equipment slot." name:"equips-to"`
+	Set      string     `help:"Set name." name:"set"`
+	MinLevel int        `default:"0" help:"Minimum level." name:"min-level"`
+	MaxLevel int        `default:"40" help:"Maximum level." name:"max-level"`
+	JSON     bool       `help:"Print the matching items as JSON instead of a table." name:"json"`
+	Inputs   InputFlags `embed:""`
+}
+
+// InputFlags selects the Trove data of the offline commands, which take
+// their inputs as flags rather than arguments.
+type InputFlags struct {
+	UploadDir string   `env:"DDO_TROVE_UPLOAD_DIR" help:"Managed upload directory to include." name:"upload-dir"`
+	Dirs      []string `env:"DDO_TROVE_DIRS" help:"Input directories or archives with Trove JSON files." name:"dir" short:"d" type:"path"`
+	Verbose   bool     `env:"DDO_TROVE_VERBOSE" help:"Enable debug logging." short:"v"`
+}
+
+func (f InputFlags) validate() error {
+	if len(f.Dirs) == 0 && f.UploadDir == "" {
+		return errors.New("at least one --dir or an upload directory is required")
+	}
+	return nil
+}
+
+func (f InputFlags) load() (*db.AllItems, error) {
+	configureLogging(f.Verbose)
+	allItems, err := loadAndAggregateItems(dataDirs(f.Dirs, f.UploadDir))
+	if err != nil {
+		return nil, fmt.Errorf("load items: %w", err)
+	}
+	return allItems, nil
+}
+
+func (c SearchCmd) Validate() error {
+	if err := c.Inputs.validate(); err != nil {
+		return err
+	}
+	if c.MinLevel > c.MaxLevel {
+		return fmt.Errorf("minimum level %d is above maximum level %d", c.MinLevel, c.MaxLevel)
+	}
+	return nil
+}
+
+func (c SearchCmd) Run() error {
+	return c.search(os.Stdout)
+}
+
+func (c SearchCmd) search(output io.Writer) error {
+	allItems, err := c.Inputs.load()
+	if err != nil {
+		return err
+	}
+	items := db.FilterItems(allItems.Items, c.Type, c.SubType, c.Char, c.Query, c.MinLevel, c.MaxLevel, c.EquipsTo, c.Set)
+	if c.JSON {
+		return writeItemsJSON(output, items)
+	}
+	return writeItemsTable(output, items)
+}
+
+func writeItemsJSON(output io.Writer, items []db.Item) error {
+	if items == nil {
+		items = []db.Item{}
+	}
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(items); err != nil {
+		return fmt.Errorf("write JSON: %w", err)
+	}
+	return nil
+}
+
+func writeItemsTable(output io.Writer, items []db.Item) error {
+	table := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tTYPE\tLEVEL\tQTY\tHOLDER\tLOCATION")
+	for _, item := range items {
+		fmt.Fprintf(table, "%s\t%s\t%d\t%d\t%s\t%s\n",
+			item.Name, itemTypeText(item), item.MinimumLevel, item.Quantity, item.CharacterName, itemLocation(item))
+	}
+	if err := table.Flush(); err != nil {
+		return fmt.Errorf("write table: %w", err)
+	}
+	return nil
+}
+
+func itemTypeText(item db.Item) string {
+	if item.ItemSubType == "" {
+		return item.ItemType
+	}
+	return item.ItemType + "/" + item.ItemSubType
+}
+
+func itemLocation(item db.Item) string {
+	parts := []string{item.Container}
+	if item.TabName != "" {
+		parts = append(parts, item.TabName)
+	} else if item.Tab > 0 {
+		parts = append(parts, "tab "+strconv.Itoa(item.Tab))
+	}
+	parts = append(parts, fmt.Sprintf("row %d col %d", item.Row, item.Column))
+	return strings.Join(parts, ", ")
+}
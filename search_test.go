@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"gotest.tools/v3/assert"
+)
+
+func TestSearchCommand(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "char.json"), []byte(`{
+		"CharacterId": 1, "Name": "Foo", "Server": "Argonnessen",
+		"Inventory": [
+			{"Name": "Fire Blade", "Container": "Inventory", "TabName": "Bag", "Row": 1, "Column": 2, "ItemType": "Weapon", "ItemSubType": "Sword", "MinimumLevel": 10, "Quantity": 1},
+			{"Name": "Ring of Fire", "Container": "Inventory", "ItemType": "Jewelry", "MinimumLevel": 3, "Quantity": 1},
+			{"Name": "Ice Blade", "Container": "Inventory", "ItemType": "Weapon", "MinimumLevel": 10, "Quantity": 1}
+		]
+	}`), 0o600))
+
+	cli, kctx, err := parseCLI([]string{"search", "fire", "--type", "Weapon", "--char", "Foo", "-d", dir})
+	assert.NilError(t, err)
+	assert.Equal(t, kctx.Command(), "search <query>")
+	var output bytes.Buffer
+	assert.NilError(t, cli.Search.search(&output))
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Equal(t, len(lines), 2)
+	assert.Assert(t, strings.HasPrefix(lines[0], "NAME "))
+	assert.Equal(t, strings.Join(strings.Fields(lines[1]), " "), "Fire Blade Weapon/Sword 10 1 Foo Inventory, Bag, row 1 col 2")
+
+	cli, _, err = parseCLI([]string{"search", "fire", "--json", "--max-level", "5", "-d", dir})
+	assert.NilError(t, err)
+	output.Reset()
+	assert.NilError(t, cli.Search.search(&output))
+	var items []db.Item
+	assert.NilError(t, json.Unmarshal(output.Bytes(), &items))
+	assert.Equal(t, len(items), 1)
+	assert.Equal(t, items[0].Name, "Ring of Fire")
+	assert.Equal(t, items[0].Server, "Argonnessen")
+
+	cli, _, err = parseCLI([]string{"search", "--json", "nothing like this", "-d", dir})
+	assert.NilError(t, err)
+	output.Reset()
+	assert.NilError(t, cli.Search.search(&output))
+	assert.Equal(t, strings.TrimSpace(output.String()), "[]")
+
+	_, _, err = parseCLI([]string{"search", "fire"})
+	assert.ErrorContains(t, err, "at least one --dir")
+	_, _, err = parseCLI([]string{"search", "--min-level", "20", "--max-level", "10", "-d", dir})
+	assert.ErrorContains(t, err, "minimum level 20 is above maximum level 10")
+}
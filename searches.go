@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const (
+	searchesPath         = "/searches"
+	searchesExportPath   = "/searches/export"
+	searchesImportPath   = "/searches/import"
+	searchesStateFile    = "saved-searches.json"
+	maxSearchImportBytes = 1 << 20
+	maxSearchNameLength  = 100
+)
+
+var errNoSearches = errors.New("no saved searches in file")
+
+// SavedSearch is a named combination of filters. The JSON form is also the
+// export and import format, wrapped as {"searches": [...]}.
+type SavedSearch struct {
+	Name   string       `json:"name"`
+	Params FilterParams `json:"params"`
+}
+
+type savedSearchesExport struct {
+	Searches []SavedSearch `json:"searches"`
+}
+
+// savedSearchesState keeps each user's searches; without user accounts
+// everything is stored under the empty name.
+type savedSearchesState struct {
+	Users map[string][]SavedSearch `json:"users"`
+}
+
+func searchOwner(r *http.Request) string {
+	if user := userFromRequest(r); user != nil {
+		return user.Name
+	}
+	return ""
+}
+
+func (a *App) loadSearches(owner string) (searches []SavedSearch, err error) {
+	var state savedSearchesState
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+	if err = loadState(a.statePath(searchesStateFile), &state); err != nil {
+		return nil, err
+	}
+	return state.Users[owner], nil
+}
+
+// updateSearches applies update to the owner's searches and stores the result
+// sorted by name.
+func (a *App) updateSearches(owner string, update func([]SavedSearch) []SavedSearch) (searches []SavedSearch, err error) {
+	path := a.statePath(searchesStateFile)
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+	var state savedSearchesState
+	if err = loadState(path, &state); err != nil {
+		return nil, err
+	}
+	if state.Users == nil {
+		state.Users = make(map[string][]SavedSearch)
+	}
+	searches = update(state.Users[owner])
+	sort.Slice(searches, func(i, j int) bool {
+		return strings.ToLower(searches[i].Name) < strings.ToLower(searches[j].Name)
+	})
+	state.Users[owner] = searches
+	if err = saveState(path, state); err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+// mergeSearches replaces searches of the same name and adds the rest.
+func mergeSearches(existing, added []SavedSearch) []SavedSearch {
+	merged := append([]SavedSearch(nil), existing...)
+	for _, search := range added {
+		replaced := false
+		for index := range merged {
+			if strings.EqualFold(merged[index].Name, search.Name) {
+				merged[index] = search
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, search)
+		}
+	}
+	return merged
+}
+
+func removeSearch(existing []SavedSearch, name string) []SavedSearch {
+	var kept []SavedSearch
+	for _, search := range existing {
+		if !strings.EqualFold(search.Name, name) {
+			kept = append(kept, search)
+		}
+	}
+	return kept
+}
+
+// savedSearchViews counts the current matches of each saved search.
+func (a *App) savedSearchViews(items []db.Item, characters []db.CharacterInfo, searches []SavedSearch) []templates.SavedSearchView {
+	views := make([]templates.SavedSearchView, 0, len(searches))
+	for _, search := range searches {
+		params := search.Params
+		matching := filterItems(filterUsableBy(items, characters, params.UsableBy), params)
+		views = append(views, templates.SavedSearchView{Name: search.Name, Path: params.indexPath(), Count: len(matching)})
+	}
+	return views
+}
+
+// handleSearches saves the posted filters under a name, or deletes the named
+// search when action is "delete". htmx requests get the refreshed sidebar.
+func (a *App) handleSearches(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	name := strings.TrimSpace(r.PostForm.Get("search_name"))
+	if name == "" || len(name) > maxSearchNameLength {
+		http.Error(w, fmt.Sprintf("search name must be 1 to %d characters", maxSearchNameLength), http.StatusBadRequest)
+		return
+	}
+
+	owner := searchOwner(r)
+	update := func(existing []SavedSearch) []SavedSearch {
+		return mergeSearches(existing, []SavedSearch{{Name: name, Params: filterParamsFromValues(r.PostForm)}})
+	}
+	if r.PostForm.Get("action") == "delete" {
+		update = func(existing []SavedSearch) []SavedSearch { return removeSearch(existing, name) }
+	}
+	searches, err := a.updateSearches(owner, update)
+	if err != nil {
+		slog.Error("save searches failed", "err", err)
+		http.Error(w, "failed to save searches", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	items, holders := a.visibleItemsAndHolders(r)
+	views := a.savedSearchViews(items, a.characterInfo(items, holders), searches)
+	if err = templates.SavedSearches(views).Render(w); err != nil {
+		slog.Error("render saved searches failed", "err", err)
+	}
+}
+
+func (a *App) handleSearchesExport(w http.ResponseWriter, r *http.Request) {
+	searches, err := a.loadSearches(searchOwner(r))
+	if err != nil {
+		slog.Error("load searches failed", "err", err)
+		http.Error(w, "failed to load searches", http.StatusInternalServerError)
+		return
+	}
+	if searches == nil {
+		searches = []SavedSearch{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="saved-searches.json"`)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(savedSearchesExport{Searches: searches}); err != nil {
+		slog.Error("write searches export failed", "err", err)
+	}
+}
+
+// handleSearchesImport merges an exported file into the user's searches;
+// imported searches replace existing ones of the same name.
+func (a *App) handleSearchesImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxSearchImportBytes)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "missing file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	imported, err := decodeSearches(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, err = a.updateSearches(searchOwner(r), func(existing []SavedSearch) []SavedSearch {
+		return mergeSearches(existing, imported)
+	}); err != nil {
+		slog.Error("import searches failed", "err", err)
+		http.Error(w, "failed to save searches", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("imported saved searches", "owner", searchOwner(r), "count", len(imported))
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func decodeSearches(reader io.Reader) (searches []SavedSearch, err error) {
+	var export struct {
+		Searches []json.RawMessage `json:"searches"`
+	}
+	if err = json.NewDecoder(reader).Decode(&export); err != nil {
+		return nil, fmt.Errorf("invalid saved searches file: %w", err)
+	}
+	for _, raw := range export.Searches {
+		// Filters missing from the file keep their defaults.
+		search := SavedSearch{Params: filterParamsFromValues(nil)}
+		if err = json.Unmarshal(raw, &search); err != nil {
+			return nil, fmt.Errorf("invalid saved search: %w", err)
+		}
+		search.Name = strings.TrimSpace(search.Name)
+		if search.Name == "" || len(search.Name) > maxSearchNameLength {
+			return nil, fmt.Errorf("invalid saved search name %q", search.Name)
+		}
+		search.Params = filterParamsFromValues(search.Params.values())
+		search.Params.Page = 0
+		searches = append(searches, search)
+	}
+	if len(searches) == 0 {
+		return nil, errNoSearches
+	}
+	return searches, nil
+}
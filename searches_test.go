@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"gotest.tools/v3/assert"
+)
+
+func TestFilterParamsValues(t *testing.T) {
+	params := filterParamsFromValues(url.Values{})
+	assert.Equal(t, params.values().Encode(), "")
+
+	params = filterParamsFromValues(url.Values{"item_type": {"Jewelry"}, "min_level": {"30"}, "usable_by": {"CharA"}})
+	assert.Equal(t, params.values().Encode(), "item_type=Jewelry&min_level=30&usable_by=CharA")
+	assert.DeepEqual(t, filterParamsFromValues(params.values()), params)
+}
+
+func TestSavedSearches(t *testing.T) {
+	items := []db.Item{
+		{Name: "Ring of Thirty", ItemType: "Jewelry", CharacterName: "CharA", MinimumLevel: 30},
+		{Name: "Low Ring", ItemType: "Jewelry", CharacterName: "CharA", MinimumLevel: 5},
+		{Name: "Sword", ItemType: "Weapon", CharacterName: "CharA", MinimumLevel: 30},
+	}
+	app := newTestApp(t, items)
+	handler := app.routes()
+
+	save := func(form url.Values) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest("POST", "/searches", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.Header.Set("HX-Request", "true")
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := save(url.Values{"search_name": {"Level 30 rings"}, "item_type": {"Jewelry"}, "min_level": {"30"}})
+	assert.Equal(t, recorder.Code, 200)
+	assert.Assert(t, strings.Contains(recorder.Body.String(), `href="/?item_type=Jewelry&amp;min_level=30"`))
+	assert.Assert(t, strings.Contains(recorder.Body.String(), "(1)"))
+
+	recorder = save(url.Values{"search_name": {"   "}})
+	assert.Equal(t, recorder.Code, 400)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+	assert.Assert(t, strings.Contains(recorder.Body.String(), "Level 30 rings"))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/searches/export", nil))
+	assert.Equal(t, recorder.Code, 200)
+	assert.Equal(t, recorder.Header().Get("Content-Type"), "application/json")
+	exported := recorder.Body.String()
+	assert.Assert(t, strings.Contains(exported, `"name": "Level 30 rings"`))
+
+	recorder = save(url.Values{"search_name": {"level 30 RINGS"}, "action": {"delete"}})
+	assert.Equal(t, recorder.Code, 200)
+	searches, err := app.loadSearches("")
+	assert.NilError(t, err)
+	assert.Equal(t, len(searches), 0)
+
+	importFile := func(content string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", "saved-searches.json")
+		assert.NilError(t, err)
+		_, err = part.Write([]byte(content))
+		assert.NilError(t, err)
+		assert.NilError(t, writer.Close())
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest("POST", "/searches/import", &body)
+		request.Header.Set("Content-Type", writer.FormDataContentType())
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder = importFile(exported)
+	assert.Equal(t, recorder.Code, 303)
+	searches, err = app.loadSearches("")
+	assert.NilError(t, err)
+	// Saved searches do not keep a page.
+	want := filterParamsFromValues(url.Values{"item_type": {"Jewelry"}, "min_level": {"30"}})
+	want.Page = 0
+	assert.DeepEqual(t, searches, []SavedSearch{{Name: "Level 30 rings", Params: want}})
+
+	recorder = importFile(`{"searches": [{"name": "Weapons", "params": {"item_type": "Weapon"}}]}`)
+	assert.Equal(t, recorder.Code, 303)
+	searches, err = app.loadSearches("")
+	assert.NilError(t, err)
+	assert.Equal(t, len(searches), 2)
+	assert.Equal(t, searches[1].Name, "Weapons")
+	assert.Equal(t, searches[1].Params.MaxLevel, defaultMaxLevel)
+
+	assert.Equal(t, importFile(`{"searches": []}`).Code, 400)
+	assert.Equal(t, importFile(`not json`).Code, 400)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	readHeaderTimeout    = 10 * time.Second
+	readTimeout          = 5 * time.Minute
+	writeTimeout         = 2 * time.Minute
+	idleTimeout          = 2 * time.Minute
+	selfSignedValidity   = 365 * 24 * time.Hour
+	selfSignedSerialBits = 128
+	basicAuthRealm       = `Basic realm="DDO Trove UI", charset="UTF-8"`
+)
+
+// basicAuthCredentials maps user names to either a plain password or a
+// hash as printed by the hash-password command.
+type basicAuthCredentials map[string]string
+
+func loadBasicAuthCredentials(cfg Config) (credentials basicAuthCredentials, err error) {
+	credentials = basicAuthCredentials{}
+	if cfg.BasicAuthUser != "" {
+		credentials[cfg.BasicAuthUser] = cfg.BasicAuthPass
+	}
+	if cfg.BasicAuthFile == "" {
+		return credentials, nil
+	}
+
+	file, err := os.Open(cfg.BasicAuthFile)
+	if err != nil {
+		return nil, fmt.Errorf("open basic auth file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, password, found := strings.Cut(line, ":")
+		if !found || name == "" || password == "" {
+			return nil, fmt.Errorf("basic auth file %q line %d: expected user:password", cfg.BasicAuthFile, lineNumber)
+		}
+		credentials[name] = password
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read basic auth file: %w", err)
+	}
+	return credentials, nil
+}
+
+// valid checks a login without revealing through its timing whether name
+// exists or how long a plain password is.
+func (c basicAuthCredentials) valid(name, password string) bool {
+	expected, exists := c[name]
+	if !exists {
+		verifyPassword(dummyPasswordHash, password)
+		return false
+	}
+	if strings.HasPrefix(expected, passwordHashScheme+"$") {
+		return verifyPassword(expected, password)
+	}
+	expectedSum := sha256.Sum256([]byte(expected))
+	passwordSum := sha256.Sum256([]byte(password))
+	return subtle.ConstantTimeCompare(expectedSum[:], passwordSum[:]) == 1
+}
+
+func (c basicAuthCredentials) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, password, ok := r.BasicAuth()
+		if !ok || !c.valid(name, password) {
+			w.Header().Set("WWW-Authenticate", basicAuthRealm)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newServer(cfg Config, handler http.Handler) (server *http.Server, err error) {
+	credentials, err := loadBasicAuthCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(credentials) > 0 {
+		handler = credentials.middleware(handler)
+	}
+
+	server = &http.Server{
+		Addr:              net.JoinHostPort(cfg.Bind, strconv.Itoa(cfg.Port)),
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+	if cfg.TLSSelfSigned {
+		certificate, certErr := selfSignedCertificate(cfg.Bind, time.Now())
+		if certErr != nil {
+			return nil, certErr
+		}
+		server.TLSConfig = &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{certificate},
+		}
+	}
+	return server, nil
+}
+
+func listenAndServe(cfg Config, server *http.Server) (err error) {
+	switch {
+	case cfg.TLSCert != "":
+		err = server.ListenAndServeTLS(cfg.TLSCert, cfg.TLSKey)
+	case cfg.TLSSelfSigned:
+		err = server.ListenAndServeTLS("", "")
+	default:
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("listen and serve: %w", err)
+	}
+	return nil
+}
+
+func (c Config) tlsEnabled() bool {
+	return c.TLSCert != "" || c.TLSSelfSigned
+}
+
+func (c Config) displayURL() string {
+	scheme := "http"
+	if c.tlsEnabled() {
+		scheme = "https"
+	}
+	host := c.Bind
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return scheme + "://" + net.JoinHostPort(host, strconv.Itoa(c.Port))
+}
+
+// selfSignedCertificate creates an in-memory certificate for localhost, the
+// machine's host name and the bind address.
+func selfSignedCertificate(bind string, now time.Time) (certificate tls.Certificate, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return certificate, fmt.Errorf("generate key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), selfSignedSerialBits))
+	if err != nil {
+		return certificate, fmt.Errorf("generate serial: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "ddo-trove-ui"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if hostname, hostErr := os.Hostname(); hostErr == nil && hostname != "" {
+		template.DNSNames = append(template.DNSNames, hostname)
+	}
+	if ip := net.ParseIP(bind); ip != nil && !ip.IsUnspecified() {
+		template.IPAddresses = append(template.IPAddresses, ip)
+	} else if bind != "" && ip == nil {
+		template.DNSNames = append(template.DNSNames, bind)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return certificate, fmt.Errorf("create certificate: %w", err)
+	}
+	slog.Info("generated self-signed certificate", "dns_names", template.DNSNames, "not_after", template.NotAfter)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBasicAuth(t *testing.T) {
+	hash, err := hashPassword("filepass")
+	assert.NilError(t, err)
+	authFile := filepath.Join(t.TempDir(), "basic-auth")
+	assert.NilError(t, os.WriteFile(authFile, []byte("# guild\nplain:plainpass\nhashed:"+hash+"\n"), 0o600))
+
+	cfg := Config{Port: defaultPort, BasicAuthUser: "env", BasicAuthPass: "envpass", BasicAuthFile: authFile}
+	server, err := newServer(cfg, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	assert.NilError(t, err)
+	assert.Equal(t, server.Addr, ":8080")
+	assert.Equal(t, server.ReadHeaderTimeout, readHeaderTimeout)
+
+	testCases := []struct {
+		name       string
+		user       string
+		password   string
+		expectCode int
+	}{
+		{name: "no credentials", expectCode: http.StatusUnauthorized},
+		{name: "env user", user: "env", password: "envpass", expectCode: http.StatusNoContent},
+		{name: "plain file user", user: "plain", password: "plainpass", expectCode: http.StatusNoContent},
+		{name: "hashed file user", user: "hashed", password: "filepass", expectCode: http.StatusNoContent},
+		{name: "wrong password", user: "plain", password: "filepass", expectCode: http.StatusUnauthorized},
+		{name: "password prefix", user: "plain", password: "plainpas", expectCode: http.StatusUnauthorized},
+		{name: "unknown user", user: "nobody", password: "plainpass", expectCode: http.StatusUnauthorized},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if testCase.user != "" {
+				request.SetBasicAuth(testCase.user, testCase.password)
+			}
+			recorder := httptest.NewRecorder()
+			server.Handler.ServeHTTP(recorder, request)
+			assert.Equal(t, recorder.Code, testCase.expectCode)
+			if testCase.expectCode == http.StatusUnauthorized {
+				assert.Equal(t, recorder.Header().Get("WWW-Authenticate"), basicAuthRealm)
+			}
+		})
+	}
+}
+
+func TestSelfSignedCertificate(t *testing.T) {
+	now := time.Now()
+	certificate, err := selfSignedCertificate("192.168.1.10", now)
+	assert.NilError(t, err)
+
+	parsed, err := x509.ParseCertificate(certificate.Certificate[0])
+	assert.NilError(t, err)
+	assert.NilError(t, parsed.VerifyHostname("localhost"))
+	assert.NilError(t, parsed.VerifyHostname("192.168.1.10"))
+	assert.Assert(t, parsed.NotAfter.After(now.Add(selfSignedValidity-time.Minute)))
+
+	server, err := newServer(Config{Bind: "127.0.0.1", Port: 8443, TLSSelfSigned: true}, http.NotFoundHandler())
+	assert.NilError(t, err)
+	assert.Equal(t, server.Addr, "127.0.0.1:8443")
+	assert.Equal(t, len(server.TLSConfig.Certificates), 1)
+}
+
+func TestDisplayURL(t *testing.T) {
+	assert.Equal(t, Config{Port: 8080}.displayURL(), "http://localhost:8080")
+	assert.Equal(t, Config{Port: 8443, Bind: "10.0.0.2", TLSSelfSigned: true}.displayURL(), "https://10.0.0.2:8443")
+	assert.Equal(t, Config{Port: 8080, Bind: "::1"}.displayURL(), "http://[::1]:8080")
+}
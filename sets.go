@@ -0,0 +1,34 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const setsPath = "/sets"
+
+func (a *App) handleSets(w http.ResponseWriter, r *http.Request) {
+	items, _ := a.visibleItemsAndHolders(r)
+
+	search := strings.TrimSpace(r.URL.Query().Get("search"))
+	sets := db.GroupItemsBySet(items)
+	if search != "" {
+		searchLower := strings.ToLower(search)
+		var matching []db.SetSummary
+		for _, set := range sets {
+			if strings.Contains(strings.ToLower(set.Name), searchLower) {
+				matching = append(matching, set)
+			}
+		}
+		sets = matching
+	}
+
+	if err := templates.Sets(sets, search).Render(w); err != nil {
+		slog.Error("render sets failed", "err", err)
+		http.Error(w, "failed to render sets", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	stateDirName    = "ddo-trove-ui"
+	stateDirPerm    = 0o750
+	stateFilePerm   = 0o640
+	tempFilePattern = ".tmp-*"
+)
+
+// resolveStateDir returns the directory for locally edited data such as
+// rules and notes, defaulting to a ddo-trove-ui directory in the user's
+// configuration directory.
+func resolveStateDir(configured string) (dir string, err error) {
+	if configured != "" {
+		return configured, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locate user config directory: %w", err)
+	}
+	return filepath.Join(configDir, stateDirName), nil
+}
+
+func (a *App) statePath(name string) string {
+	return filepath.Join(a.stateDir, name)
+}
+
+// loadState decodes a JSON state file into value; a missing file leaves
+// value untouched.
+func loadState(path string, value any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read state %q: %w", path, err)
+	}
+	if err = json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("parse state %q: %w", path, err)
+	}
+	return nil
+}
+
+func saveState(path string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode state %q: %w", path, err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), stateDirPerm); err != nil {
+		return fmt.Errorf("create state directory: %w", err)
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+func writeFileAtomic(path string, data []byte) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), tempFilePattern)
+	if err != nil {
+		return fmt.Errorf("create temp file for %q: %w", path, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write %q: %w", tmp.Name(), err)
+	}
+	if err = tmp.Chmod(stateFilePerm); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("chmod %q: %w", tmp.Name(), err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("close %q: %w", tmp.Name(), err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename to %q: %w", path, err)
+	}
+	return nil
+}
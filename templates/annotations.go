@@ -0,0 +1,69 @@
+package templates
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components" //nolint:revive,staticcheck
+	. "maragu.dev/gomponents/html"       //nolint:revive,staticcheck
+)
+
+const annotationsEndpoint = "/annotations"
+
+func annotationPath(item db.Item) string {
+	return annotationsEndpoint + "?" + url.Values{"key": {item.Identity()}}.Encode()
+}
+
+// Annotation edits the note, tags and favorite flag of one item.
+func Annotation(item db.Item) g.Node {
+	annotation := item.Annotation
+	return Layout("DDO Trove UI - "+item.Name,
+		H1(g.Text(item.Name)),
+		Div(Class("static-tooltip"), itemTooltip(item)),
+		Form(Class("filter-controls"), Method("post"), Action(annotationsEndpoint),
+			Input(Type("hidden"), Name("key"), Value(item.Identity())),
+			Div(Class("filter-row"),
+				Label(For("annotationTags"), g.Text("Tags:")),
+				Input(Type("text"), ID("annotationTags"), Name("tags"), Value(strings.Join(annotation.Tags, ", ")), Placeholder("keep for TR, guild loan, sell")),
+				Label(For("annotationFavorite"), g.Text("Favorite:")),
+				Input(Type("checkbox"), ID("annotationFavorite"), Name("favorite"), Value("1"), g.If(annotation.Favorite, Checked())),
+			),
+			Div(Class("filter-row"),
+				Label(For("annotationNote"), g.Text("Note:")),
+				Textarea(ID("annotationNote"), Name("note"), Rows("6"), Cols("60"), MaxLength("4000"), g.Text(annotation.Note)),
+			),
+			Div(Class("filter-row"),
+				Button(Type("submit"), Class(paginationClass), g.Text("Save")),
+			),
+		),
+	)
+}
+
+// FavoriteButton toggles the item's favorite flag in place.
+func FavoriteButton(item db.Item) g.Node {
+	label, title := "☆", "Add to favorites"
+	if item.Annotation.Favorite {
+		label, title = "★", "Remove from favorites"
+	}
+	return Button(Classes{"favorite-button": true, "active": item.Annotation.Favorite}, Title(title),
+		Data("hx-post", annotationsEndpoint),
+		Data("hx-vals", hxVals(map[string]string{"key": item.Identity(), "action": "favorite"})),
+		Data("hx-swap", "outerHTML"),
+		g.Text(label),
+	)
+}
+
+// itemAnnotation shows the item's tags and note under its name, with a link
+// to edit them.
+func itemAnnotation(item db.Item) g.Node {
+	annotation := item.Annotation
+	return Div(Class("item-annotation"),
+		g.Group(g.Map(annotation.Tags, func(tag string) g.Node { //nolint:unconvert
+			return Span(Class("item-tag"), g.Text(tag))
+		})),
+		g.If(annotation.Note != "", Span(Class("item-note"), g.Text(annotation.Note))),
+		A(Class("item-annotate"), Href(annotationPath(item)), Title("Edit notes and tags"), g.Text("✎")),
+	)
+}
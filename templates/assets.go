@@ -0,0 +1,39 @@
+package templates
+
+import (
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html" //nolint:revive,staticcheck
+)
+
+const (
+	staticURLPrefix = "/static/"
+	styleAsset      = "style.css"
+	htmxAsset       = "vendor/htmx.min.js"
+	htmxCDNURL      = "https://unpkg.com/htmx.org@1.9.10"
+	htmxIntegrity   = "sha384-D1Kt99CQMDuVetoL1lrYwg5t+9QdHe7NLX/SoJYkXDFfX37iInKRy5xLSi8nO7UC"
+)
+
+var assetURLs = map[string]string{}
+
+// SetAssetURLs sets the URLs used for static assets, keyed by their path
+// below static/. It must be called before rendering starts.
+func SetAssetURLs(urls map[string]string) {
+	assetURLs = urls
+}
+
+func assetURL(name string) string {
+	if url, exists := assetURLs[name]; exists {
+		return url
+	}
+	return staticURLPrefix + name
+}
+
+// htmxScript prefers the pinned local copy and falls back to the CDN when it
+// has not been fetched into static/vendor.
+func htmxScript() g.Node {
+	src := htmxCDNURL
+	if url, exists := assetURLs[htmxAsset]; exists {
+		src = url
+	}
+	return Script(Src(src), Integrity(htmxIntegrity), g.Attr("crossorigin", "anonymous"))
+}
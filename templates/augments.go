@@ -0,0 +1,145 @@
+package templates
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html" //nolint:revive,staticcheck
+)
+
+const (
+	augmentsEndpoint = "/augments"
+	unknownColor     = "Unknown"
+)
+
+func Augments(gearItems []db.Item, selected *db.Item, plans []db.AugmentSlotPlan, augmentsByColor map[string][]db.Item, maxLevel int) g.Node {
+	return Layout("DDO Trove UI - Augments",
+		H1(g.Text("Augment Slot Planner")),
+		augmentColorSummary(augmentsByColor),
+		g.Iff(selected != nil, func() g.Node { return augmentPlan(selected, plans, maxLevel) }),
+		H2(g.Text("Gear with open augment slots")),
+		Div(Class("item-list"),
+			g.If(len(gearItems) == 0, P(g.Text("No gear with open augment slots found."))),
+			g.Group(g.Map(gearItems, func(item db.Item) g.Node { //nolint:unconvert
+				return Div(Class("item-row"),
+					g.If(item.IconSource != "", Img(Src(item.IconSource), Alt("Item Icon"), Class("item-icon"))),
+					Div(Class("item-name"), A(Href(augmentGearPath(item, maxLevel)), g.Text(item.Name))),
+					Div(Class("item-type"), g.Text(item.ItemType)),
+					Div(Class("item-character"), g.Text(item.CharacterName)),
+					Div(Class("item-min-level"), g.Text(fmt.Sprintf("Lvl: %d", item.MinimumLevel))),
+					Div(Class("item-quantity"), g.Text(fmt.Sprintf("Open: %d", openSlotCount(item)))),
+					Div(Class("item-equips-to"), g.Text("Slots: "+slotColors(item))),
+					itemTooltip(item),
+				)
+			})),
+		),
+	)
+}
+
+func augmentGearPath(item db.Item, maxLevel int) string {
+	values := url.Values{}
+	values.Set("key", item.Identity())
+	if maxLevel > 0 {
+		values.Set("max_level", strconv.Itoa(maxLevel))
+	}
+	return augmentsEndpoint + "?" + values.Encode()
+}
+
+func augmentColorSummary(augmentsByColor map[string][]db.Item) g.Node {
+	colors := make([]string, 0, len(augmentsByColor))
+	for color := range augmentsByColor {
+		colors = append(colors, color)
+	}
+	sort.Strings(colors)
+
+	return Div(Class("summary-list"),
+		g.Group(g.Map(colors, func(color string) g.Node { //nolint:unconvert
+			label := color
+			if label == "" {
+				label = unknownColor
+			}
+			return Span(Class("summary-chip augment-"+strings.ToLower(label)),
+				g.Text(fmt.Sprintf("%s: %d", label, len(augmentsByColor[color]))))
+		})),
+	)
+}
+
+func augmentPlan(gear *db.Item, plans []db.AugmentSlotPlan, maxLevel int) g.Node {
+	return Div(Class("item-list augment-plan"),
+		H2(g.Text(fmt.Sprintf("%s (%s, level %d)", gear.Name, gear.CharacterName, gear.MinimumLevel))),
+		Form(Class("filter-controls"), Method("get"), Action(augmentsEndpoint),
+			Input(Type("hidden"), Name("key"), Value(gear.Identity())),
+			Div(Class("filter-row"),
+				Label(For("augmentMaxLevel"), g.Text("Max augment level:")),
+				Input(Type("number"), ID("augmentMaxLevel"), Name("max_level"), Value(strconv.Itoa(maxLevel)), Min("0"), Max("40")),
+				Button(Type("submit"), Class("pagination-button"), g.Text("Apply")),
+			),
+		),
+		g.Group(g.Map(plans, func(plan db.AugmentSlotPlan) g.Node { //nolint:unconvert
+			return augmentSlotPlan(gear, plan)
+		})),
+	)
+}
+
+func augmentSlotPlan(gear *db.Item, plan db.AugmentSlotPlan) g.Node {
+	if !plan.Empty {
+		return Div(Class("augment-slot"),
+			H3(g.Text(fmt.Sprintf("Slot %d: %s", plan.Index+1, plan.Slot.Color))),
+			P(g.Text("Filled: "+plan.Slot.Name)),
+		)
+	}
+	return Div(Class("augment-slot"),
+		H3(g.Text(fmt.Sprintf("Slot %d: %s (open)", plan.Index+1, plan.Slot.Color))),
+		g.If(len(plan.Candidates) == 0, P(g.Text("No owned augment fits this slot."))),
+		g.If(len(plan.Candidates) > 0, Table(Class("data-table"),
+			THead(Tr(Th(g.Text("Augment")), Th(g.Text("Color")), Th(g.Text("Level")), Th(g.Text("Holder")), Th(g.Text("Effects")))),
+			TBody(g.Group(g.Map(plan.Candidates, func(augment db.Item) g.Node { //nolint:unconvert
+				return Tr(
+					Td(g.Text(augment.Name)),
+					Td(g.Text(db.AugmentColor(augment))),
+					Td(
+						g.If(augment.MinimumLevel > gear.MinimumLevel, Class("level-warning")),
+						g.Text(strconv.Itoa(augment.MinimumLevel)),
+						g.If(augment.MinimumLevel > gear.MinimumLevel, g.Text(" (raises item level)")),
+					),
+					Td(g.Text(augment.CharacterName)),
+					Td(g.Text(effectSummary(augment))),
+				)
+			}))),
+		)),
+	)
+}
+
+func openSlotCount(item db.Item) int {
+	count := 0
+	for _, slot := range item.AugmentSlots {
+		if slot.IsEmpty() {
+			count++
+		}
+	}
+	return count
+}
+
+func slotColors(item db.Item) string {
+	colors := make([]string, 0, len(item.AugmentSlots))
+	for _, slot := range item.AugmentSlots {
+		colors = append(colors, slot.Color)
+	}
+	return strings.Join(colors, ", ")
+}
+
+func effectSummary(item db.Item) string {
+	names := make([]string, 0, len(item.Effects))
+	for _, effect := range item.Effects {
+		names = append(names, effect.Name)
+	}
+	if len(names) == 0 {
+		return item.Description
+	}
+	return strings.Join(names, ", ")
+}
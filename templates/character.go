@@ -0,0 +1,79 @@
+package templates
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html" //nolint:revive,staticcheck
+)
+
+const characterEndpoint = "/character"
+
+func Character(holders []string, selected string, grids []db.ContainerGrid) g.Node {
+	return Layout("DDO Trove UI - "+selected,
+		H1(g.Text("Character Containers")),
+		Form(Class("filter-controls"), Method("get"), Action(characterEndpoint),
+			Div(Class("filter-row"),
+				Label(For("holderSelect"), g.Text("Character or bank:")),
+				Select(ID("holderSelect"), Name("name"), g.Attr("onchange", "this.form.submit()"),
+					g.If(selected == "", Option(Value(""), g.Text("Choose..."), Selected())),
+					g.Group(g.Map(holders, func(holder string) g.Node { //nolint:unconvert
+						return selectedOption(holder, selected)
+					})),
+				),
+			),
+		),
+		g.If(selected != "" && len(grids) == 0, P(g.Text("No items found for "+selected+"."))),
+		g.Group(g.Map(grids, containerGrid)), //nolint:unconvert
+	)
+}
+
+func characterPath(holder string) string {
+	return characterEndpoint + "?" + url.Values{"name": {holder}}.Encode()
+}
+
+func containerGrid(grid db.ContainerGrid) g.Node {
+	title := grid.Container
+	if grid.TabName != "" {
+		title += " - " + grid.TabName
+	}
+	title += fmt.Sprintf(" (Tab %d)", grid.Tab)
+
+	var cells []g.Node
+	for row := range grid.Cells {
+		for column, item := range grid.Cells[row] {
+			cells = append(cells, gridCell(item, row, column))
+		}
+	}
+
+	return Div(Class("item-list container-grid"),
+		H2(g.Text(title)),
+		Div(Class("grid-cells"),
+			Style("grid-template-columns: repeat("+strconv.Itoa(grid.Columns)+", 44px)"),
+			g.Group(cells),
+		),
+		g.If(len(grid.Unplaced) > 0, Div(
+			P(Strong(g.Text("Sharing a slot with another item:"))),
+			g.Group(g.Map(grid.Unplaced, renderItem)), //nolint:unconvert
+		)),
+	)
+}
+
+func gridCell(item *db.Item, row, column int) g.Node {
+	if item == nil {
+		return Div(Class("grid-cell empty"), Title(fmt.Sprintf("Row %d, Col %d", row+1, column+1)))
+	}
+	label := item.Name
+	if item.Quantity > 1 {
+		label = fmt.Sprintf("%s x%d", item.Name, item.Quantity)
+	}
+	return Div(Class("grid-cell"), Title(label),
+		g.If(item.IconSource != "", Img(Src(item.IconSource), Alt(item.Name), Class("item-icon"))),
+		g.If(item.IconSource == "", Span(Class("grid-cell-text"), g.Text(item.Name))),
+		g.If(item.Quantity > 1, Span(Class("grid-cell-quantity"), g.Text(strconv.Itoa(item.Quantity)))),
+		itemTooltip(*item),
+	)
+}
@@ -0,0 +1,70 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components" //nolint:revive,staticcheck
+	. "maragu.dev/gomponents/html"       //nolint:revive,staticcheck
+)
+
+const clickiesEndpoint = "/clickies"
+
+func Clickies(clickies []db.ClickySummary, spell, sortBy string) g.Node {
+	sortOption := func(value, label string) g.Node {
+		return Option(Value(value), g.Text(label), g.If(value == sortBy, Selected()))
+	}
+	return Layout("DDO Trove UI - Clickies",
+		H1(g.Text("Clickies")),
+		Form(Class("filter-controls"), Method("get"), Action(clickiesEndpoint),
+			Div(Class("filter-row"),
+				Label(For("clickySpell"), g.Text("Spell:")),
+				Input(Type("text"), ID("clickySpell"), Name("spell"), Value(spell), Placeholder("Filter spells...")),
+				Label(For("clickySort"), g.Text("Sort by:")),
+				Select(ID("clickySort"), Name("sort"),
+					sortOption(db.ClickySortSpell, "Spell"),
+					sortOption(db.ClickySortCharges, "Fewest charges"),
+					sortOption(db.ClickySortChargesDesc, "Most charges"),
+				),
+				Button(Type("submit"), Class("pagination-button"), g.Text("Filter")),
+			),
+		),
+		P(Class("item-count"), g.Text(fmt.Sprintf("Found %d spells.", len(clickies)))),
+		g.Group(g.Map(clickies, clickySummary)), //nolint:unconvert
+	)
+}
+
+func clickySummary(clicky db.ClickySummary) g.Node {
+	return Div(Class("item-list"),
+		H2(g.Text(clicky.Spell)),
+		P(g.Text(fmt.Sprintf("%d items, %s charges left.", len(clicky.Items), chargesText(clicky.Charges, clicky.MaxCharges)))),
+		Table(Class("data-table"),
+			THead(Tr(Th(g.Text("Item")), Th(g.Text("Caster level")), Th(g.Text("Charges")), Th(g.Text("Targets")), Th(g.Text("Holder")))),
+			TBody(g.Group(g.Map(clicky.Items, func(item db.Item) g.Node { //nolint:unconvert
+				return Tr(
+					Td(Class("tooltip-cell"), itemNameDiv(item), itemTooltip(item)),
+					Td(g.Text(strconv.Itoa(item.Clicky.CasterLevel))),
+					Td(Classes{"charges-low": item.MaxCharges > 0 && item.Charges*lowChargesDivisor <= item.MaxCharges},
+						g.Text(chargesText(item.Charges, item.MaxCharges))),
+					Td(g.Text(strings.Join(item.Clicky.ValidTargets, ", "))),
+					Td(A(Href(characterPath(item.CharacterName)), g.Text(item.CharacterName)), g.Text(" - "+item.Container)),
+				)
+			}))),
+		),
+	)
+}
+
+// lowChargesDivisor flags items with at most a fifth of their charges left.
+const lowChargesDivisor = 5
+
+// chargesText shows remaining out of max charges; items without a maximum
+// do not track charges.
+func chargesText(charges, maxCharges int) string {
+	if maxCharges == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", charges, maxCharges)
+}
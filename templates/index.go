@@ -15,104 +15,204 @@ const (
 	changeTrigger       = "change"
 	inputTrigger        = "input changed delay:500ms"
 
-	includeTypeFilter      = "#itemSubTypeFilter, #characterFilter, #nameSearch, #minLevel, #maxLevel, #equipsToFilter"
-	includeSubTypeFilter   = "#itemTypeFilter, #characterFilter, #nameSearch, #minLevel, #maxLevel, #equipsToFilter"
-	includeCharacterFilter = "#itemTypeFilter, #itemSubTypeFilter, #nameSearch, #minLevel, #maxLevel, #equipsToFilter"
-	includeEquipsToFilter  = "#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #nameSearch, #minLevel, #maxLevel"
-	includeMinLevel        = "#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #nameSearch, #maxLevel, #equipsToFilter"
-	includeMaxLevel        = "#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #nameSearch, #minLevel, #equipsToFilter"
-	includeNameSearch      = "#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #minLevel, #maxLevel, #equipsToFilter"
+	includeTypeFilter      = "#itemSubTypeFilter, #characterFilter, #nameSearch, #minLevel, #maxLevel, #equipsToFilter, #setFilter, #usableByFilter, #tagFilter, #favoritesFilter"
+	includeSubTypeFilter   = "#itemTypeFilter, #characterFilter, #nameSearch, #minLevel, #maxLevel, #equipsToFilter, #setFilter, #usableByFilter, #tagFilter, #favoritesFilter"
+	includeCharacterFilter = "#itemTypeFilter, #itemSubTypeFilter, #nameSearch, #minLevel, #maxLevel, #equipsToFilter, #setFilter, #usableByFilter, #tagFilter, #favoritesFilter"
+	includeEquipsToFilter  = "#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #nameSearch, #minLevel, #maxLevel, #setFilter, #usableByFilter, #tagFilter, #favoritesFilter"
+	includeSetFilter       = "#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #nameSearch, #minLevel, #maxLevel, #equipsToFilter, #usableByFilter, #tagFilter, #favoritesFilter"
+	includeMinLevel        = "#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #nameSearch, #maxLevel, #equipsToFilter, #setFilter, #usableByFilter, #tagFilter, #favoritesFilter"
+	includeMaxLevel        = "#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #nameSearch, #minLevel, #equipsToFilter, #setFilter, #usableByFilter, #tagFilter, #favoritesFilter"
+	includeNameSearch      = "#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #minLevel, #maxLevel, #equipsToFilter, #setFilter, #usableByFilter, #tagFilter, #favoritesFilter"
+	includeAllFilters      = "#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #nameSearch, #minLevel, #maxLevel, #equipsToFilter, #setFilter, #usableByFilter, #tagFilter, #favoritesFilter"
+	includeUsableByFilter  = "#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #nameSearch, #minLevel, #maxLevel, #equipsToFilter, #setFilter, #tagFilter, #favoritesFilter"
+	includeTagFilter       = "#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #nameSearch, #minLevel, #maxLevel, #equipsToFilter, #setFilter, #usableByFilter, #favoritesFilter"
+	includeFavoritesFilter = "#itemTypeFilter, #itemSubTypeFilter, #characterFilter, #nameSearch, #minLevel, #maxLevel, #equipsToFilter, #setFilter, #usableByFilter, #tagFilter"
 )
 
-func Index(items []db.Item, itemTypes []string, selectedType string, itemSubTypes []string, selectedSubType string, characterNames []string, selectedCharacter string, minLevel, maxLevel, currentPage, totalPages, totalFilteredItemsCount int, uniqueEquipsTo []string, selectedEquipsTo string) g.Node {
+// IndexParams are the selected filters. Its fields mirror the main package's
+// FilterParams so that handlers can convert one into the other.
+type IndexParams struct {
+	ItemType      string
+	ItemSubType   string
+	CharacterName string
+	NameSearch    string
+	EquipsTo      string
+	SetName       string
+	UsableBy      string
+	Tag           string
+	Favorites     bool
+	MinLevel      int
+	MaxLevel      int
+	Page          int
+}
+
+// IndexOptions are the choices offered by the filter selects.
+type IndexOptions struct {
+	ItemTypes      []string
+	ItemSubTypes   []string
+	CharacterNames []string
+	EquipsTo       []string
+	SetNames       []string
+	UsableBy       []string
+	Tags           []string
+}
+
+type IndexView struct {
+	// Items is the current page of filtered items.
+	Items         []db.Item
+	Page          int
+	TotalPages    int
+	TotalCount    int
+	Params        IndexParams
+	Options       IndexOptions
+	SavedSearches []SavedSearchView
+	UserName      string
+}
+
+func Index(view IndexView) g.Node {
 	return Layout("DDO Trove UI",
+		userBar(view.UserName),
 		H1(g.Text("DDO Trove Item Browser")),
-		Div(Class("filter-controls"),
-			Div(Class("filter-row"),
-				Label(For("itemTypeFilter"), g.Text("Filter by Item Type:")),
-				Select(
-					ID("itemTypeFilter"), Name("item_type"),
-					Data("hx-get", itemsEndpoint),
-					Data("hx-target", itemListContainerID),
-					Data("hx-swap", hxSwapMode),
-					Data("hx-trigger", changeTrigger),
-					Data("hx-include", includeTypeFilter),
-					selectedOption(db.FilterAll, selectedType),
-					g.Group(g.Map(itemTypes, func(itemType string) g.Node { //nolint:unconvert
-						return selectedOption(itemType, selectedType)
-					})),
-				),
-				Label(For("itemSubTypeFilter"), g.Text("Item Sub Type:")),
-				Select(
-					ID("itemSubTypeFilter"), Name("item_sub_type"),
-					Data("hx-get", itemsEndpoint),
-					Data("hx-target", itemListContainerID),
-					Data("hx-swap", hxSwapMode),
-					Data("hx-trigger", changeTrigger),
-					Data("hx-include", includeSubTypeFilter),
-					selectedOption(db.FilterAll, selectedSubType),
-					g.Group(g.Map(itemSubTypes, func(itemSubType string) g.Node { //nolint:unconvert
-						return selectedOption(itemSubType, selectedSubType)
-					})),
+		Div(Class("index-layout"),
+			SavedSearches(view.SavedSearches),
+			Div(Class("index-main"),
+				Div(Class("filter-controls"),
+					Div(Class("filter-row"),
+						Label(For("itemTypeFilter"), g.Text("Filter by Item Type:")),
+						Select(
+							ID("itemTypeFilter"), Name("item_type"),
+							Data("hx-get", itemsEndpoint),
+							Data("hx-target", itemListContainerID),
+							Data("hx-swap", hxSwapMode),
+							Data("hx-trigger", changeTrigger),
+							Data("hx-include", includeTypeFilter),
+							selectedOption(db.FilterAll, view.Params.ItemType),
+							g.Group(g.Map(view.Options.ItemTypes, func(itemType string) g.Node { //nolint:unconvert
+								return selectedOption(itemType, view.Params.ItemType)
+							})),
+						),
+						Label(For("itemSubTypeFilter"), g.Text("Item Sub Type:")),
+						Select(
+							ID("itemSubTypeFilter"), Name("item_sub_type"),
+							Data("hx-get", itemsEndpoint),
+							Data("hx-target", itemListContainerID),
+							Data("hx-swap", hxSwapMode),
+							Data("hx-trigger", changeTrigger),
+							Data("hx-include", includeSubTypeFilter),
+							selectedOption(db.FilterAll, view.Params.ItemSubType),
+							g.Group(g.Map(view.Options.ItemSubTypes, func(itemSubType string) g.Node { //nolint:unconvert
+								return selectedOption(itemSubType, view.Params.ItemSubType)
+							})),
+						),
+						Label(For("characterFilter"), g.Text("Character:")),
+						Select(
+							ID("characterFilter"), Name("character_name"),
+							Data("hx-get", itemsEndpoint),
+							Data("hx-target", itemListContainerID),
+							Data("hx-swap", hxSwapMode),
+							Data("hx-trigger", changeTrigger),
+							Data("hx-include", includeCharacterFilter),
+							selectedOption(db.FilterAll, view.Params.CharacterName),
+							g.Group(g.Map(view.Options.CharacterNames, func(charName string) g.Node { //nolint:unconvert
+								return selectedOption(charName, view.Params.CharacterName)
+							})),
+						),
+					),
+					Div(Class("filter-row"),
+						Label(For("equipsToFilter"), g.Text("Equips To:")),
+						Select(
+							ID("equipsToFilter"), Name("equips_to"),
+							Data("hx-get", itemsEndpoint),
+							Data("hx-target", itemListContainerID),
+							Data("hx-swap", hxSwapMode),
+							Data("hx-trigger", changeTrigger),
+							Data("hx-include", includeEquipsToFilter),
+							selectedOption(db.FilterAll, view.Params.EquipsTo),
+							g.Group(g.Map(view.Options.EquipsTo, func(equipsTo string) g.Node { //nolint:unconvert
+								return selectedOption(equipsTo, view.Params.EquipsTo)
+							})),
+						),
+						Label(For("setFilter"), g.Text("Set:")),
+						Select(
+							ID("setFilter"), Name("set_name"),
+							Data("hx-get", itemsEndpoint),
+							Data("hx-target", itemListContainerID),
+							Data("hx-swap", hxSwapMode),
+							Data("hx-trigger", changeTrigger),
+							Data("hx-include", includeSetFilter),
+							selectedOption(db.FilterAll, view.Params.SetName),
+							g.Group(g.Map(view.Options.SetNames, func(setName string) g.Node { //nolint:unconvert
+								return selectedOption(setName, view.Params.SetName)
+							})),
+						),
+						Label(For("usableByFilter"), g.Text("Usable by:")),
+						Select(
+							ID("usableByFilter"), Name("usable_by"),
+							Data("hx-get", itemsEndpoint),
+							Data("hx-target", itemListContainerID),
+							Data("hx-swap", hxSwapMode),
+							Data("hx-trigger", changeTrigger),
+							Data("hx-include", includeUsableByFilter),
+							selectedOption(db.FilterAll, view.Params.UsableBy),
+							g.Group(g.Map(view.Options.UsableBy, func(name string) g.Node { //nolint:unconvert
+								return selectedOption(name, view.Params.UsableBy)
+							})),
+						),
+					),
+					Div(Class("filter-row"),
+						Label(For("tagFilter"), g.Text("Tag:")),
+						Select(
+							ID("tagFilter"), Name("tag"),
+							Data("hx-get", itemsEndpoint),
+							Data("hx-target", itemListContainerID),
+							Data("hx-swap", hxSwapMode),
+							Data("hx-trigger", changeTrigger),
+							Data("hx-include", includeTagFilter),
+							selectedOption(db.FilterAll, view.Params.Tag),
+							g.Group(g.Map(view.Options.Tags, func(tag string) g.Node { //nolint:unconvert
+								return selectedOption(tag, view.Params.Tag)
+							})),
+						),
+						Label(For("favoritesFilter"), g.Text("Favorites only:")),
+						Input(Type("checkbox"), ID("favoritesFilter"), Name("favorites"), Value("1"), g.If(view.Params.Favorites, Checked()),
+							Data("hx-get", itemsEndpoint),
+							Data("hx-target", itemListContainerID),
+							Data("hx-swap", hxSwapMode),
+							Data("hx-trigger", changeTrigger),
+							Data("hx-include", includeFavoritesFilter),
+						),
+					),
+					Div(Class("filter-row"),
+						Label(For("minLevel"), g.Text("Min Level:")),
+						Input(Type("number"), ID("minLevel"), Name("min_level"), Value(strconv.Itoa(view.Params.MinLevel)), Min("0"), Max("40"),
+							Data("hx-get", itemsEndpoint),
+							Data("hx-target", itemListContainerID),
+							Data("hx-swap", hxSwapMode),
+							Data("hx-trigger", inputTrigger),
+							Data("hx-include", includeMinLevel),
+						),
+						Label(For("maxLevel"), g.Text("Max Level:")),
+						Input(Type("number"), ID("maxLevel"), Name("max_level"), Value(strconv.Itoa(view.Params.MaxLevel)), Min("0"), Max("40"),
+							Data("hx-get", itemsEndpoint),
+							Data("hx-target", itemListContainerID),
+							Data("hx-swap", hxSwapMode),
+							Data("hx-trigger", inputTrigger),
+							Data("hx-include", includeMaxLevel),
+						),
+						Label(For("nameSearch"), g.Text("Full Text Search:")),
+						Input(Type("text"), ID("nameSearch"), Name("name_search"), Value(view.Params.NameSearch), Placeholder("Search names, effects, descriptions..."),
+							Data("hx-get", itemsEndpoint),
+							Data("hx-target", itemListContainerID),
+							Data("hx-swap", hxSwapMode),
+							Data("hx-trigger", inputTrigger),
+							Data("hx-include", includeNameSearch),
+						),
+					),
 				),
-				Label(For("characterFilter"), g.Text("Character:")),
-				Select(
-					ID("characterFilter"), Name("character_name"),
-					Data("hx-get", itemsEndpoint),
-					Data("hx-target", itemListContainerID),
-					Data("hx-swap", hxSwapMode),
-					Data("hx-trigger", changeTrigger),
-					Data("hx-include", includeCharacterFilter),
-					selectedOption(db.FilterAll, selectedCharacter),
-					g.Group(g.Map(characterNames, func(charName string) g.Node { //nolint:unconvert
-						return selectedOption(charName, selectedCharacter)
-					})),
+				Div(ID("item-list-container"),
+					ItemList(view.Items, view.Page, view.TotalPages, view.TotalCount),
 				),
 			),
-			Div(Class("filter-row"),
-				Label(For("equipsToFilter"), g.Text("Equips To:")),
-				Select(
-					ID("equipsToFilter"), Name("equips_to"),
-					Data("hx-get", itemsEndpoint),
-					Data("hx-target", itemListContainerID),
-					Data("hx-swap", hxSwapMode),
-					Data("hx-trigger", changeTrigger),
-					Data("hx-include", includeEquipsToFilter),
-					selectedOption(db.FilterAll, selectedEquipsTo),
-					g.Group(g.Map(uniqueEquipsTo, func(equipsTo string) g.Node { //nolint:unconvert
-						return selectedOption(equipsTo, selectedEquipsTo)
-					})),
-				),
-			),
-			Div(Class("filter-row"),
-				Label(For("minLevel"), g.Text("Min Level:")),
-				Input(Type("number"), ID("minLevel"), Name("min_level"), Value(strconv.Itoa(minLevel)), Min("0"), Max("40"),
-					Data("hx-get", itemsEndpoint),
-					Data("hx-target", itemListContainerID),
-					Data("hx-swap", hxSwapMode),
-					Data("hx-trigger", inputTrigger),
-					Data("hx-include", includeMinLevel),
-				),
-				Label(For("maxLevel"), g.Text("Max Level:")),
-				Input(Type("number"), ID("maxLevel"), Name("max_level"), Value(strconv.Itoa(maxLevel)), Min("0"), Max("40"),
-					Data("hx-get", itemsEndpoint),
-					Data("hx-target", itemListContainerID),
-					Data("hx-swap", hxSwapMode),
-					Data("hx-trigger", inputTrigger),
-					Data("hx-include", includeMaxLevel),
-				),
-				Label(For("nameSearch"), g.Text("Full Text Search:")),
-				Input(Type("text"), ID("nameSearch"), Name("name_search"), Placeholder("Search names, effects, descriptions..."),
-					Data("hx-get", itemsEndpoint),
-					Data("hx-target", itemListContainerID),
-					Data("hx-swap", hxSwapMode),
-					Data("hx-trigger", inputTrigger),
-					Data("hx-include", includeNameSearch),
-				),
-			),
-		),
-		Div(ID("item-list-container"), Data("hx-preserve", "true"),
-			ItemList(items, selectedType, selectedSubType, selectedCharacter, currentPage, totalPages, totalFilteredItemsCount, selectedEquipsTo),
 		),
 	)
 }
@@ -0,0 +1,81 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components" //nolint:revive,staticcheck
+	. "maragu.dev/gomponents/html"       //nolint:revive,staticcheck
+)
+
+const (
+	ingredientsEndpoint = "/ingredients"
+	targetsExample      = `# One ingredient per line: <name>: <quantity>
+Cannith Essence: 200
+Bone Fragment: 5`
+)
+
+func Ingredients(totals, targeted []db.IngredientTotal, search, targets, message string) g.Node {
+	missing := 0
+	for _, total := range targeted {
+		if total.Missing() > 0 {
+			missing++
+		}
+	}
+	return Layout("DDO Trove UI - Ingredients",
+		H1(g.Text("Ingredients and Collectibles")),
+		Form(Class("filter-controls"), Method("get"), Action(ingredientsEndpoint),
+			g.If(message != "", P(Class("form-error"), g.Text(message))),
+			Div(Class("filter-row"),
+				Label(For("ingredientSearch"), g.Text("Name:")),
+				Input(Type("text"), ID("ingredientSearch"), Name("search"), Value(search), Placeholder("Filter ingredients...")),
+			),
+			Label(For("targets"), g.Text("Target amounts:")),
+			Textarea(ID("targets"), Name("targets"), Class("rules-input"), Rows("6"), Placeholder(targetsExample), g.Text(targets)),
+			Div(Class("filter-row"),
+				Button(Type("submit"), Class("pagination-button"), g.Text("Update")),
+			),
+		),
+		g.If(len(targeted) > 0, Div(Class("item-list"),
+			H2(g.Text("Targets")),
+			P(Class("item-count"), g.Text(fmt.Sprintf("%d of %d ingredients short.", missing, len(targeted)))),
+			Table(Class("data-table"),
+				THead(Tr(Th(g.Text("Ingredient")), Th(g.Text("Owned")), Th(g.Text("Target")), Th(g.Text("Missing")), Th(g.Text("Held by")))),
+				TBody(g.Group(g.Map(targeted, func(total db.IngredientTotal) g.Node { //nolint:unconvert
+					return Tr(
+						Td(g.Text(total.Name)),
+						Td(g.Text(strconv.Itoa(total.Quantity))),
+						Td(g.Text(strconv.Itoa(total.Target))),
+						Td(Classes{"shortfall": total.Missing() > 0}, g.Text(strconv.Itoa(total.Missing()))),
+						Td(g.Text(holdingsText(total.Holders))),
+					)
+				}))),
+			),
+		)),
+		Div(Class("item-list"),
+			H2(g.Text("All ingredients")),
+			P(Class("item-count"), g.Text(fmt.Sprintf("Found %d ingredients.", len(totals)))),
+			Table(Class("data-table"),
+				THead(Tr(Th(g.Text("Ingredient")), Th(g.Text("Owned")), Th(g.Text("Held by")))),
+				TBody(g.Group(g.Map(totals, func(total db.IngredientTotal) g.Node { //nolint:unconvert
+					return Tr(
+						Td(g.Text(total.Name)),
+						Td(g.Text(strconv.Itoa(total.Quantity))),
+						Td(g.Text(holdingsText(total.Holders))),
+					)
+				}))),
+			),
+		),
+	)
+}
+
+func holdingsText(holdings []db.IngredientHolding) string {
+	parts := make([]string, 0, len(holdings))
+	for _, holding := range holdings {
+		parts = append(parts, fmt.Sprintf("%s %d", holding.Holder, holding.Quantity))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -13,14 +13,14 @@ import (
 )
 
 const (
-	paginationInclude = "#nameSearch, #minLevel, #maxLevel, #equipsToFilter"
+	paginationInclude = includeAllFilters
 	paginationClass   = "pagination-button"
 	btcSuffix         = " (BTC)"
 )
 
-func ItemList(items []db.Item, selectedType, selectedSubType, selectedCharacter string, currentPage, totalPages, totalFilteredItemsCount int, selectedEquipsTo string) g.Node {
+func ItemList(items []db.Item, currentPage, totalPages, totalFilteredItemsCount int) g.Node {
 	return g.Group([]g.Node{
-		paginationControls(selectedType, selectedSubType, selectedCharacter, currentPage, totalPages, selectedEquipsTo),
+		paginationControls(currentPage, totalPages),
 		Div(Class("pagination-controls")),
 		P(Class("item-count"), g.Text(fmt.Sprintf("Found %d items.", totalFilteredItemsCount))),
 		Div(Class("item-list"),
@@ -29,63 +29,41 @@ func ItemList(items []db.Item, selectedType, selectedSubType, selectedCharacter
 			),
 			g.Group(g.Map(items, renderItem)), //nolint:unconvert
 		),
-		paginationControls(selectedType, selectedSubType, selectedCharacter, currentPage, totalPages, selectedEquipsTo),
+		paginationControls(currentPage, totalPages),
 	})
 }
 
-func paginationPath(selectedType, selectedSubType, selectedCharacter string, page int, selectedEquipsTo string) string {
-	values := url.Values{}
-	values.Set("item_type", selectedType)
-	values.Set("item_sub_type", selectedSubType)
-	values.Set("character_name", selectedCharacter)
-	values.Set("page", strconv.Itoa(page))
-	values.Set("equips_to", selectedEquipsTo)
-	return fmt.Sprintf("%s?%s", itemsEndpoint, values.Encode())
+// paginationPath only carries the page; the filters come from the inputs
+// via paginationInclude, so the server sees the same state as for a filter
+// change.
+func paginationPath(page int) string {
+	return itemsEndpoint + "?" + url.Values{"page": {strconv.Itoa(page)}}.Encode()
 }
 
-func paginationControls(selectedType, selectedSubType, selectedCharacter string, currentPage, totalPages int, selectedEquipsTo string) g.Node {
+func paginationButton(label string, page int, active bool) g.Node {
+	return Button(
+		Classes{paginationClass: true, "active": active},
+		Data("hx-get", paginationPath(page)),
+		Data("hx-target", itemListContainerID),
+		Data("hx-swap", hxSwapMode),
+		Data("hx-include", paginationInclude),
+		g.Text(label),
+	)
+}
+
+func paginationControls(currentPage, totalPages int) g.Node {
 	return Div(Class("pagination-controls"),
-		g.If(currentPage > 1,
-			Button(
-				Class(paginationClass),
-				Data("hx-get", paginationPath(selectedType, selectedSubType, selectedCharacter, currentPage-1, selectedEquipsTo)),
-				Data("hx-target", itemListContainerID),
-				Data("hx-swap", hxSwapMode),
-				Data("hx-include", paginationInclude),
-				g.Text("Previous"),
-			),
-		),
-		generatePageButtons(selectedType, selectedSubType, selectedCharacter, currentPage, totalPages, selectedEquipsTo),
-		g.If(currentPage < totalPages,
-			Button(
-				Class(paginationClass),
-				Data("hx-get", paginationPath(selectedType, selectedSubType, selectedCharacter, currentPage+1, selectedEquipsTo)),
-				Data("hx-target", itemListContainerID),
-				Data("hx-swap", hxSwapMode),
-				Data("hx-include", paginationInclude),
-				g.Text("Next"),
-			),
-		),
+		g.If(currentPage > 1, paginationButton("Previous", currentPage-1, false)),
+		generatePageButtons(currentPage, totalPages),
+		g.If(currentPage < totalPages, paginationButton("Next", currentPage+1, false)),
 	)
 }
 
-func generatePageButtons(selectedType, selectedSubType, selectedCharacter string, currentPage, totalPages int, selectedEquipsTo string) g.Node {
+func generatePageButtons(currentPage, totalPages int) g.Node {
 	var buttons []g.Node
-	pageRange := getPageRange(currentPage, totalPages)
-
-	for _, page := range pageRange {
-		buttons = append(buttons,
-			Button(
-				Classes{paginationClass: true, "active": page == currentPage},
-				Data("hx-get", paginationPath(selectedType, selectedSubType, selectedCharacter, page, selectedEquipsTo)),
-				Data("hx-target", itemListContainerID),
-				Data("hx-swap", hxSwapMode),
-				Data("hx-include", paginationInclude),
-				g.Text(strconv.Itoa(page)),
-			),
-		)
+	for _, page := range getPageRange(currentPage, totalPages) {
+		buttons = append(buttons, paginationButton(strconv.Itoa(page), page, page == currentPage))
 	}
-
 	return g.Group(buttons)
 }
 
@@ -121,7 +99,7 @@ func renderItem(item db.Item) g.Node {
 		),
 		itemNameDiv(item),
 		Div(Class("item-type"), g.Text(item.ItemType)),
-		Div(Class("item-character"), g.Text(item.CharacterName)),
+		Div(Class("item-character"), A(Href(characterPath(item.CharacterName)), g.Text(item.CharacterName))),
 		Div(Class("item-min-level"), g.Text(fmt.Sprintf("Lvl: %d", item.MinimumLevel))),
 		Div(Class("item-quantity"), g.Text(fmt.Sprintf("Qty: %d", item.Quantity))),
 		Div(Class("item-equips-to"), g.Text("Equips: "+strings.Join(item.EquipsTo, ", "))),
@@ -130,16 +108,22 @@ func renderItem(item db.Item) g.Node {
 }
 
 func itemNameDiv(item db.Item) g.Node {
-	if item.Binding == db.BindingBoundToCharacter {
-		return Div(Class("item-name btc"), g.Text(item.Name+btcSuffix))
+	btc := item.BindingState() == db.BindingBoundToCharacter
+	name := item.Name
+	if btc {
+		name += btcSuffix
 	}
-	return Div(Class("item-name"), g.Text(item.Name))
+	return Div(Classes{"item-name": true, "btc": btc},
+		FavoriteButton(item),
+		g.Text(name),
+		itemAnnotation(item),
+	)
 }
 
 func itemTooltip(item db.Item) g.Node {
 	var content []g.Node
 
-	if item.Binding == db.BindingBoundToCharacter {
+	if item.BindingState() == db.BindingBoundToCharacter {
 		content = append(content, H4(Class("btc"), g.Text(item.Name+btcSuffix)))
 	} else {
 		content = append(content, H4(g.Text(item.Name)))
@@ -153,6 +137,30 @@ func itemTooltip(item db.Item) g.Node {
 		labeledText("Location", fmt.Sprintf("%s - %s (Tab %d), Row %d, Col %d", item.Container, item.TabName, item.Tab, item.Row, item.Column)),
 	)
 
+	if item.BaseValueCopper > 0 {
+		value := db.FormatCopper(item.BaseValueCopper)
+		if item.Quantity > 1 {
+			value += " each, " + db.FormatCopper(item.VendorValue()) + " total"
+		}
+		content = append(content, labeledText("Vendor Value", value))
+	}
+
+	if binding := item.BindingState(); binding != db.BindingUnbound {
+		content = append(content, labeledText("Binding", binding.String()))
+	}
+
+	if item.Annotation.Favorite {
+		content = append(content, labeledText("Favorite", "yes"))
+	}
+
+	if len(item.Annotation.Tags) > 0 {
+		content = append(content, labeledText("Tags", strings.Join(item.Annotation.Tags, ", ")))
+	}
+
+	if item.Annotation.Note != "" {
+		content = append(content, labeledText("Note", item.Annotation.Note))
+	}
+
 	if len(item.EquipsTo) > 0 {
 		content = append(content, labeledText("Equips To", strings.Join(item.EquipsTo, ", ")))
 	}
@@ -162,7 +170,20 @@ func itemTooltip(item db.Item) g.Node {
 	}
 
 	if item.Clicky != nil {
-		content = append(content, labeledText("Clicky", fmt.Sprintf("%s (CL %d)", item.Clicky.SpellName, item.Clicky.CasterLevel)))
+		clicky := fmt.Sprintf("%s (CL %d)", item.Clicky.SpellName, item.Clicky.CasterLevel)
+		if item.MaxCharges > 0 {
+			clicky += ", " + chargesText(item.Charges, item.MaxCharges) + " charges"
+		}
+		content = append(content, labeledText("Clicky", clicky))
+	}
+
+	if item.SetBonus1Name != "" {
+		content = append(content, labeledText("Set", item.SetBonus1Name))
+		var bonuses []g.Node
+		for _, description := range item.SetBonus1Description {
+			bonuses = append(bonuses, Li(g.Text(description)))
+		}
+		content = append(content, g.El("ul", g.Group(bonuses)))
 	}
 
 	if len(item.AugmentSlots) > 0 {
@@ -0,0 +1,71 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html" //nolint:revive,staticcheck
+)
+
+const junkEndpoint = "/junk"
+
+func Junk(options db.JunkOptions, junk []db.JunkItem, totals []db.ContainerValue) g.Node {
+	total := 0
+	for _, entry := range junk {
+		total += entry.Value
+	}
+	return Layout("DDO Trove UI - Junk",
+		H1(g.Text("Junk Report")),
+		P(g.Text("Items worth considering for the vendor, most valuable first. Equipped items and favorites are never listed; outgrown uses character levels from profiles or equipped gear.")),
+		Form(Class("filter-controls"), Method("get"), Action(junkEndpoint),
+			Div(Class("filter-row"),
+				Label(For("junkBelow"), g.Text("Below level:")),
+				Input(Type("number"), ID("junkBelow"), Name("below"), Value(strconv.Itoa(options.BelowLevel)), Min("0"), Max("40")),
+				Label(For("junkOutgrownBy"), g.Text("Outgrown by levels:")),
+				Input(Type("number"), ID("junkOutgrownBy"), Name("outgrown_by"), Value(strconv.Itoa(options.OutgrownBy)), Min("0"), Max("40")),
+				Label(For("junkDuplicates"), g.Text("Duplicates:")),
+				Input(Type("checkbox"), ID("junkDuplicates"), Name("duplicates"), Value("1"), g.If(options.Duplicates, Checked())),
+				Label(For("junkNonBTC"), g.Text("Skip BTC:")),
+				Input(Type("checkbox"), ID("junkNonBTC"), Name("non_btc"), Value("1"), g.If(options.NonBTCOnly, Checked())),
+				Button(Type("submit"), Class("pagination-button"), g.Text("Update")),
+			),
+		),
+		P(Class("item-count"), g.Text(fmt.Sprintf("Found %d items worth %s.", len(junk), db.FormatCopper(total)))),
+		g.If(len(totals) > 0, Div(Class("item-list"),
+			H2(g.Text("By container")),
+			Table(Class("data-table"),
+				THead(Tr(Th(g.Text("Holder")), Th(g.Text("Container")), Th(g.Text("Items")), Th(g.Text("Value")))),
+				TBody(g.Group(g.Map(totals, func(total db.ContainerValue) g.Node { //nolint:unconvert
+					return Tr(
+						Td(A(Href(characterPath(total.Holder)), g.Text(total.Holder))),
+						Td(g.Text(total.Container)),
+						Td(g.Text(strconv.Itoa(total.Count))),
+						Td(Class("currency"), g.Text(db.FormatCopper(total.Value))),
+					)
+				}))),
+			),
+		)),
+		g.If(len(junk) > 0, Div(Class("item-list"),
+			H2(g.Text("Items")),
+			Table(Class("data-table"),
+				THead(Tr(Th(g.Text("Item")), Th(g.Text("Level")), Th(g.Text("Quantity")), Th(g.Text("Holder")), Th(g.Text("Why")), Th(g.Text("Value")))),
+				TBody(g.Group(g.Map(junk, junkRow))), //nolint:unconvert
+			),
+		)),
+	)
+}
+
+func junkRow(entry db.JunkItem) g.Node {
+	item := entry.Item
+	return Tr(
+		Td(Class("tooltip-cell"), itemNameDiv(item), itemTooltip(item)),
+		Td(g.Text(strconv.Itoa(item.MinimumLevel))),
+		Td(g.Text(strconv.Itoa(item.Quantity))),
+		Td(A(Href(characterPath(item.CharacterName)), g.Text(item.CharacterName)), g.Text(" - "+item.Container)),
+		Td(g.Text(strings.Join(entry.Reasons, ", "))),
+		Td(Class("currency"), g.Text(db.FormatCopper(entry.Value))),
+	)
+}
@@ -1,27 +1,58 @@
 package templates
 
 import (
+	"encoding/json"
+
 	g "maragu.dev/gomponents"
 	. "maragu.dev/gomponents/html" //nolint:revive,staticcheck
 )
 
+const htmxConfig = `{"historyCacheSize":0,"refreshOnHistoryMiss":true}`
+
 func Layout(title string, children ...g.Node) g.Node {
 	return Doctype(
 		HTML(Lang("en"),
 			Head(
 				Meta(Charset("UTF-8")),
 				Meta(Name("viewport"), Content("width=device-width, initial-scale=1.0")),
+				// Pages are rendered from the URL, so history navigation reloads
+				// instead of restoring snapshots that lose form values.
+				Meta(Name("htmx-config"), Content(htmxConfig)),
 				TitleEl(g.Text(title)),
-				Script(Src("https://unpkg.com/htmx.org@1.9.10"),
-					Integrity("sha384-D1Kt99CQMDuVetoL1lrYwg5t+9QdHe7NLX/SoJYkXDFfX37iInKRy5xLSi8nO7UC"),
-					g.Attr("crossorigin", "anonymous")),
-				Link(Rel("stylesheet"), Href("/static/style.css")),
+				htmxScript(),
+				Link(Rel("stylesheet"), Href(assetURL(styleAsset))),
 			),
 			Body(
 				Div(Class("container"),
+					navigation(),
 					g.Group(children),
 				),
 			),
 		),
 	)
 }
+
+func navigation() g.Node {
+	return Nav(Class("site-nav"),
+		A(Href("/"), g.Text("Items")),
+		A(Href(characterEndpoint), g.Text("Characters")),
+		A(Href(augmentsEndpoint), g.Text("Augments")),
+		A(Href(setsEndpoint), g.Text("Sets")),
+		A(Href(clickiesEndpoint), g.Text("Clickies")),
+		A(Href(ingredientsEndpoint), g.Text("Ingredients")),
+		A(Href(recipesEndpoint), g.Text("Recipes")),
+		A(Href(profilesEndpoint), g.Text("Profiles")),
+		A(Href(transfersEndpoint), g.Text("Transfers")),
+		A(Href(upgradesEndpoint), g.Text("Upgrades")),
+		A(Href(reorgEndpoint), g.Text("Reorganize")),
+		A(Href(junkEndpoint), g.Text("Junk")),
+		A(Href(wishListEndpoint), g.Text("Wish List")),
+	)
+}
+
+// hxVals encodes values for an hx-vals attribute.
+func hxVals(values map[string]string) string {
+	// Marshaling a map of strings cannot fail.
+	encoded, _ := json.Marshal(values)
+	return string(encoded)
+}
@@ -0,0 +1,36 @@
+package templates
+
+import (
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html" //nolint:revive,staticcheck
+)
+
+const (
+	loginEndpoint  = "/login"
+	logoutEndpoint = "/logout"
+)
+
+func Login(message string) g.Node {
+	return Layout("DDO Trove UI - Login",
+		H1(g.Text("DDO Trove Item Browser")),
+		Form(Class("filter-controls"), Method("post"), Action(loginEndpoint),
+			g.If(message != "", P(Class("form-error"), g.Text(message))),
+			Div(Class("filter-row"),
+				Label(For("username"), g.Text("Username:")),
+				Input(Type("text"), ID("username"), Name("username"), AutoComplete("username"), Required()),
+				Label(For("password"), g.Text("Password:")),
+				Input(Type("password"), ID("password"), Name("password"), AutoComplete("current-password"), Required()),
+				Button(Type("submit"), Class("pagination-button"), g.Text("Log in")),
+			),
+		),
+	)
+}
+
+func userBar(userName string) g.Node {
+	return g.If(userName != "",
+		Div(Class("user-bar"),
+			g.Text("Signed in as "+userName+" · "),
+			A(Href(logoutEndpoint), g.Text("Log out")),
+		),
+	)
+}
@@ -0,0 +1,90 @@
+package templates
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html" //nolint:revive,staticcheck
+)
+
+const profilesEndpoint = "/profiles"
+
+// Profiles lists the effective info of every character and, for the selected
+// one, a form editing its stored profile. Inferred values show as
+// placeholders, so empty fields keep them.
+func Profiles(characters []db.CharacterInfo, selected string, profile *db.CharacterProfile, inferred db.CharacterInfo) g.Node {
+	names := make([]string, 0, len(characters))
+	for _, character := range characters {
+		names = append(names, character.Name)
+	}
+	return Layout("DDO Trove UI - Profiles",
+		H1(g.Text("Character Profiles")),
+		P(g.Text("Trove does not record level, class or proficiencies. Values here override what is inferred from equipped gear and are used by the usable-by filter and transfer suggestions.")),
+		Form(Class("filter-controls"), Method("get"), Action(profilesEndpoint),
+			Div(Class("filter-row"),
+				Label(For("profileSelect"), g.Text("Character:")),
+				Select(ID("profileSelect"), Name("name"), g.Attr("onchange", "this.form.submit()"),
+					g.If(selected == "", Option(Value(""), g.Text("Choose..."), Selected())),
+					g.Group(g.Map(names, func(name string) g.Node { //nolint:unconvert
+						return selectedOption(name, selected)
+					})),
+				),
+			),
+		),
+		g.Iff(profile != nil, func() g.Node { return profileForm(*profile, inferred) }),
+		Div(Class("item-list"),
+			Table(Class("data-table"),
+				THead(Tr(
+					Th(g.Text("Character")), Th(g.Text("Level")), Th(g.Text("Race")), Th(g.Text("Classes")),
+					Th(g.Text("Proficiencies")), Th(g.Text("Weapon types")), Th(g.Text("Armor")),
+				)),
+				TBody(g.Group(g.Map(characters, func(character db.CharacterInfo) g.Node { //nolint:unconvert
+					return Tr(
+						Td(A(Href(profilesEndpoint+"?"+url.Values{"name": {character.Name}}.Encode()), g.Text(character.Name))),
+						Td(g.Text(levelText(character.Level))),
+						Td(g.Text(character.Race)),
+						Td(g.Text(strings.Join(character.Classes, ", "))),
+						Td(g.Text(strings.Join(character.Proficiencies, ", "))),
+						Td(g.Text(strings.Join(character.WeaponTypes, ", "))),
+						Td(g.Text(strings.Join(character.ArmorTypes, ", "))),
+					)
+				}))),
+			),
+		),
+	)
+}
+
+func profileForm(profile db.CharacterProfile, inferred db.CharacterInfo) g.Node {
+	level := ""
+	if profile.Level > 0 {
+		level = strconv.Itoa(profile.Level)
+	}
+	return Form(Class("filter-controls profile-form"), Method("post"), Action(profilesEndpoint),
+		Input(Type("hidden"), Name("name"), Value(profile.Name)),
+		H2(g.Text(profile.Name)),
+		Div(Class("filter-row"),
+			Label(For("profileLevel"), g.Text("Level:")),
+			Input(Type("number"), ID("profileLevel"), Name("level"), Value(level), Min("1"), Max("40"), Placeholder(levelText(inferred.Level))),
+			Label(For("profileRace"), g.Text("Race:")),
+			Input(Type("text"), ID("profileRace"), Name("race"), Value(profile.Race)),
+			Label(For("profileClasses"), g.Text("Classes:")),
+			Input(Type("text"), ID("profileClasses"), Name("classes"), Value(strings.Join(profile.Classes, ", ")), Placeholder("Fighter, Rogue")),
+		),
+		Div(Class("filter-row"),
+			Label(For("profileProficiencies"), g.Text("Proficiencies:")),
+			Input(Type("text"), ID("profileProficiencies"), Name("proficiencies"), Value(strings.Join(profile.Proficiencies, ", ")),
+				Placeholder(strings.Join(inferred.Proficiencies, ", "))),
+			Label(For("profileWeaponTypes"), g.Text("Weapon types:")),
+			Input(Type("text"), ID("profileWeaponTypes"), Name("weapon_types"), Value(strings.Join(profile.WeaponTypes, ", ")), Placeholder("Khopesh, Bastard Sword")),
+			Label(For("profileArmorTypes"), g.Text("Armor types:")),
+			Input(Type("text"), ID("profileArmorTypes"), Name("armor_types"), Value(strings.Join(profile.ArmorTypes, ", ")),
+				Placeholder(strings.Join(inferred.ArmorTypes, ", "))),
+		),
+		Div(Class("filter-row"),
+			Button(Type("submit"), Class("pagination-button"), g.Text("Save profile")),
+		),
+	)
+}
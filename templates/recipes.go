@@ -0,0 +1,74 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components" //nolint:revive,staticcheck
+	. "maragu.dev/gomponents/html"       //nolint:revive,staticcheck
+)
+
+const (
+	recipesEndpoint = "/recipes"
+	recipesExample  = `{"recipes": [
+  {"name": "Tier 1 Green Steel", "group": "Green Steel", "ingredients": [
+    {"name": "Shroud Gem", "quantity": 2},
+    {"name": "Cannith Essence", "quantity": 100}
+  ]}
+]}`
+)
+
+func Recipes(path, message string, checks []db.RecipeCheck) g.Node {
+	completable := 0
+	for _, check := range checks {
+		if check.Completable() {
+			completable++
+		}
+	}
+	return Layout("DDO Trove UI - Recipes",
+		H1(g.Text("Crafting Recipes")),
+		P(g.Text("Recipes are read from "), Code(g.Text(path)), g.Text(" and checked against the ingredients of all visible holders.")),
+		g.If(message != "", P(Class("form-error"), g.Text(message))),
+		g.If(message == "" && len(checks) == 0, Div(
+			P(g.Text("No recipes defined yet. Create the file with recipes like:")),
+			Pre(g.Text(recipesExample)),
+		)),
+		g.If(len(checks) > 0, P(Class("item-count"), g.Text(fmt.Sprintf("%d of %d recipes completable now.", completable, len(checks))))),
+		g.Group(g.Map(checks, recipeCheck)), //nolint:unconvert
+	)
+}
+
+func recipeCheck(check db.RecipeCheck) g.Node {
+	title := check.Recipe.Name
+	if check.Recipe.Group != "" {
+		title = check.Recipe.Group + ": " + title
+	}
+	status := "Short of ingredients"
+	if check.Completable() {
+		status = "Completable"
+	}
+	return Div(Class("item-list"),
+		H2(g.Text(title+" "), Span(Classes{"recipe-status": true, "recipe-completable": check.Completable()}, g.Text(status))),
+		Table(Class("data-table"),
+			THead(Tr(Th(g.Text("Ingredient")), Th(g.Text("Needed")), Th(g.Text("Owned")), Th(g.Text("Missing")), Th(g.Text("Held by")))),
+			TBody(g.Group(g.Map(check.Ingredients, func(total db.IngredientTotal) g.Node { //nolint:unconvert
+				return Tr(
+					Td(g.Text(total.Name)),
+					Td(g.Text(strconv.Itoa(total.Target))),
+					Td(g.Text(strconv.Itoa(total.Quantity))),
+					Td(Classes{"shortfall": total.Missing() > 0}, g.Text(strconv.Itoa(total.Missing()))),
+					Td(g.Text(holdingsText(total.Holders))),
+				)
+			}))),
+		),
+		g.If(check.Crafter != "", P(g.Text("Craft on "), A(Href(characterPath(check.Crafter)), g.Text(check.Crafter)),
+			g.If(len(check.Handovers) == 0, g.Text(", who holds everything needed.")),
+			g.If(len(check.Handovers) > 0, g.Text(" after these handovers:")),
+		)),
+		g.If(len(check.Handovers) > 0, Ul(g.Group(g.Map(check.Handovers, func(handover db.Handover) g.Node { //nolint:unconvert
+			return Li(g.Text(fmt.Sprintf("%s: %d %s", handover.From, handover.Quantity, handover.Ingredient)))
+		})))),
+	)
+}
@@ -0,0 +1,75 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html" //nolint:revive,staticcheck
+)
+
+const (
+	reorgEndpoint = "/reorg"
+	rulesExample  = `# One rule per line, first match wins: <match> => <holder>[, <container>[, <tab>]]
+type=Augment => Account (Shared Bank), SharedBank, 2
+type=Ingredient => Account (Crafting Bank)
+binding=BoundToCharacter => owner`
+)
+
+func Reorg(rules, message string, moves []db.Move, skipped []db.SkippedMove) g.Node {
+	return Layout("DDO Trove UI - Reorganize",
+		H1(g.Text("Bank Reorganization Planner")),
+		Form(Class("filter-controls"), Method("post"), Action(reorgEndpoint),
+			g.If(message != "", P(Class("form-error"), g.Text(message))),
+			Label(For("rules"), g.Text("Rules (match terms: type, subtype, name, binding, set, equips, holder, container; * matches all):")),
+			Textarea(ID("rules"), Name("rules"), Class("rules-input"), Rows("8"), Placeholder(rulesExample), g.Text(rules)),
+			Div(Class("filter-row"),
+				Button(Type("submit"), Class("pagination-button"), g.Text("Save and plan")),
+			),
+		),
+		g.If(message == "" && rules != "", P(Class("item-count"), g.Text(fmt.Sprintf("%d moves planned, %d items cannot be moved.", len(moves), len(skipped))))),
+		g.If(len(moves) > 0, Div(Class("item-list"),
+			H2(g.Text("Moves")),
+			Ol(Class("move-list"), g.Group(g.Map(moves, moveStep))), //nolint:unconvert
+		)),
+		g.If(len(skipped) > 0, Div(Class("item-list"),
+			H2(g.Text("Cannot move")),
+			Table(Class("data-table"),
+				THead(Tr(Th(g.Text("Item")), Th(g.Text("Now in")), Th(g.Text("Rule")), Th(g.Text("Reason")))),
+				TBody(g.Group(g.Map(skipped, func(skip db.SkippedMove) g.Node { //nolint:unconvert
+					return Tr(
+						Td(g.Text(skip.Item.Name)),
+						Td(g.Text(skip.Item.CharacterName+" / "+skip.Item.Container)),
+						Td(g.Text(fmt.Sprintf("line %d: %s", skip.Rule.Line, skip.Rule.Text))),
+						Td(g.Text(skip.Reason)),
+					)
+				}))),
+			),
+		)),
+	)
+}
+
+func moveStep(move db.Move) g.Node {
+	id := fmt.Sprintf("move-%s-%d-%d-%d-%d", move.From.Container, move.From.Tab, move.From.Row, move.From.Column, move.Item.ItemID)
+	return Li(
+		Input(Type("checkbox"), ID(id)),
+		Label(For(id),
+			Strong(g.Text(move.Item.Name)),
+			g.If(move.Item.Quantity > 1, g.Text(" x"+strconv.Itoa(move.Item.Quantity))),
+			g.Text(": "+locationText(move.From)+" → "+locationText(move.To)),
+		),
+		Span(Class("move-rule"), g.Text(fmt.Sprintf(" (line %d)", move.Rule.Line))),
+	)
+}
+
+func locationText(location db.Location) string {
+	text := location.Holder + " / " + location.Container
+	if location.Tab != db.AnyTab {
+		text += fmt.Sprintf(" tab %d", location.Tab)
+	}
+	if location.HasSlot {
+		text += fmt.Sprintf(" (row %d, col %d)", location.Row, location.Column)
+	}
+	return text
+}
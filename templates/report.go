@@ -0,0 +1,201 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html" //nolint:revive,staticcheck
+)
+
+// Static report layout; all links are relative so the site also works when
+// opened from disk.
+const (
+	ReportIndexPage       = "index.html"
+	ReportCharactersPage  = "characters.html"
+	ReportStyleSheet      = "style.css"
+	ReportSearchIndexFile = "search-index.js"
+	reportCharactersDir   = "characters/"
+	reportItemsDir        = "items/"
+	reportSubdirRoot      = "../"
+	reportMaxResults      = 200
+)
+
+var reportSlugPattern = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// ReportHolder is a character or bank listed in the report.
+type ReportHolder struct {
+	Name      string
+	Server    string
+	ItemCount int
+}
+
+func reportSlug(value string) string {
+	return reportSlugPattern.ReplaceAllString(value, "_")
+}
+
+// ReportPaths names the character and item pages, relative to the site root.
+// Different names can share a slug ("Foo Bar", "Foo_Bar" and "Foo.Bar"), so
+// a name whose page file is already taken, ignoring case, gets a numeric
+// suffix. Names keep the page they were first given.
+type ReportPaths struct {
+	pages map[string]string
+	taken map[string]bool
+}
+
+func NewReportPaths() *ReportPaths {
+	return &ReportPaths{pages: make(map[string]string), taken: make(map[string]bool)}
+}
+
+// Character is the page of the holder on server.
+func (p *ReportPaths) Character(server, holder string) string {
+	return p.page(reportCharactersDir, server+"/"+holder)
+}
+
+// Item is the page of item. Items with the same identity share a page.
+func (p *ReportPaths) Item(item db.Item) string {
+	return p.page(reportItemsDir, item.Identity())
+}
+
+func (p *ReportPaths) page(dir, key string) string {
+	if path, exists := p.pages[dir+key]; exists {
+		return path
+	}
+	slug := reportSlug(key)
+	path := dir + slug + ".html"
+	for suffix := 2; p.taken[strings.ToLower(path)]; suffix++ {
+		path = fmt.Sprintf("%s%s-%d.html", dir, slug, suffix)
+	}
+	p.taken[strings.ToLower(path)] = true
+	p.pages[dir+key] = path
+	return path
+}
+
+func reportLayout(title, root string, children ...g.Node) g.Node {
+	return Doctype(
+		HTML(Lang("en"),
+			Head(
+				Meta(Charset("UTF-8")),
+				Meta(Name("viewport"), Content("width=device-width, initial-scale=1.0")),
+				TitleEl(g.Text(title)),
+				Link(Rel("stylesheet"), Href(root+ReportStyleSheet)),
+			),
+			Body(
+				Div(Class("container"),
+					Nav(Class("site-nav"),
+						A(Href(root+ReportIndexPage), g.Text("Search")),
+						A(Href(root+ReportCharactersPage), g.Text("Characters")),
+					),
+					g.Group(children),
+				),
+			),
+		),
+	)
+}
+
+// ReportIndex searches the items through the search index script.
+func ReportIndex(itemCount int, generated string) g.Node {
+	return reportLayout("DDO Trove Report", "",
+		H1(g.Text("DDO Trove Report")),
+		P(Class("item-count"), g.Text(fmt.Sprintf("%d items, generated %s.", itemCount, generated))),
+		Div(Class("filter-controls"),
+			Div(Class("filter-row"),
+				Label(For("reportSearch"), g.Text("Full Text Search:")),
+				Input(Type("text"), ID("reportSearch"), Placeholder("Search names, effects, descriptions..."), AutoFocus()),
+			),
+		),
+		P(ID("reportSearchCount"), Class("item-count")),
+		Div(Class("item-list"), Ul(ID("reportSearchResults"), Class("report-results"))),
+		Script(Src(ReportSearchIndexFile)),
+		Script(g.Raw(reportSearchScript)),
+	)
+}
+
+// reportSearchScript matches every search word against the entries of
+// search-index.js, showing names first as on the live site.
+var reportSearchScript = `
+(function () {
+  var input = document.getElementById("reportSearch");
+  var results = document.getElementById("reportSearchResults");
+  var count = document.getElementById("reportSearchCount");
+  var entries = window.troveSearchIndex || [];
+  function render() {
+    var words = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    var matches = entries.filter(function (entry) {
+      return words.every(function (word) { return entry.text.indexOf(word) >= 0; });
+    });
+    matches.sort(function (a, b) {
+      var an = words.every(function (word) { return a.name.toLowerCase().indexOf(word) >= 0; });
+      var bn = words.every(function (word) { return b.name.toLowerCase().indexOf(word) >= 0; });
+      return an === bn ? a.name.localeCompare(b.name) : (an ? -1 : 1);
+    });
+    count.textContent = "Found " + matches.length + " items.";
+    results.replaceChildren.apply(results, matches.slice(0, ` + strconv.Itoa(reportMaxResults) + `).map(function (entry) {
+      var item = document.createElement("li");
+      var link = document.createElement("a");
+      link.href = entry.url;
+      link.textContent = entry.name;
+      item.append(link, " - " + entry.type + ", level " + entry.level + ", " + entry.holder);
+      return item;
+    }));
+  }
+  input.addEventListener("input", render);
+  render();
+})();
+`
+
+func ReportCharacters(holders []ReportHolder, paths *ReportPaths) g.Node {
+	return reportLayout("DDO Trove Report - Characters", "",
+		H1(g.Text("Characters and Banks")),
+		Table(Class("data-table"),
+			THead(Tr(Th(g.Text("Name")), Th(g.Text("Server")), Th(g.Text("Items")))),
+			TBody(g.Group(g.Map(holders, func(holder ReportHolder) g.Node { //nolint:unconvert
+				return Tr(
+					Td(A(Href(paths.Character(holder.Server, holder.Name)), g.Text(holder.Name))),
+					Td(g.Text(holder.Server)),
+					Td(g.Text(strconv.Itoa(holder.ItemCount))),
+				)
+			}))),
+		),
+	)
+}
+
+// ReportCharacter lists what holder carries, sorted as given.
+func ReportCharacter(holder string, items []db.Item, paths *ReportPaths) g.Node {
+	return reportLayout("DDO Trove Report - "+holder, reportSubdirRoot,
+		H1(g.Text(holder)),
+		P(Class("item-count"), g.Text(fmt.Sprintf("%d items.", len(items)))),
+		Table(Class("data-table"),
+			THead(Tr(Th(g.Text("Item")), Th(g.Text("Type")), Th(g.Text("Level")), Th(g.Text("Quantity")), Th(g.Text("Location")))),
+			TBody(g.Group(g.Map(items, func(item db.Item) g.Node { //nolint:unconvert
+				return Tr(
+					Td(Class("tooltip-cell"), A(Href(reportSubdirRoot+paths.Item(item)), g.Text(item.Name)), itemTooltip(item)),
+					Td(g.Text(item.ItemType)),
+					Td(g.Text(strconv.Itoa(item.MinimumLevel))),
+					Td(g.Text(strconv.Itoa(item.Quantity))),
+					Td(g.Text(fmt.Sprintf("%s - %s (Tab %d), Row %d, Col %d", item.Container, item.TabName, item.Tab, item.Row, item.Column))),
+				)
+			}))),
+		),
+	)
+}
+
+// ReportItem shows the details of an item and every holder of a copy.
+func ReportItem(copies []db.Item, paths *ReportPaths) g.Node {
+	item := copies[0]
+	return reportLayout("DDO Trove Report - "+item.Name, reportSubdirRoot,
+		H1(g.Text(item.Name)),
+		Div(Class("static-tooltip"), itemTooltip(item)),
+		g.If(len(copies) > 1, Div(Class("item-list"),
+			H2(g.Text("Held by")),
+			Ul(g.Group(g.Map(copies, func(copied db.Item) g.Node { //nolint:unconvert
+				return Li(A(Href(reportSubdirRoot+paths.Character(copied.Server, copied.CharacterName)), g.Text(copied.CharacterName)),
+					g.Text(fmt.Sprintf(" - %s, quantity %d", copied.Container, copied.Quantity)))
+			}))),
+		)),
+		g.If(len(copies) == 1, P(A(Href(reportSubdirRoot+paths.Character(item.Server, item.CharacterName)), g.Text("Held by "+item.CharacterName)))),
+	)
+}
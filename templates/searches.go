@@ -0,0 +1,64 @@
+package templates
+
+import (
+	"strconv"
+
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html" //nolint:revive,staticcheck
+)
+
+const (
+	searchesEndpoint       = "/searches"
+	searchesExportEndpoint = "/searches/export"
+	searchesImportEndpoint = "/searches/import"
+	savedSearchesID        = "saved-searches"
+	includeSaveSearch      = "#savedSearchName, " + includeAllFilters
+)
+
+type SavedSearchView struct {
+	Name  string
+	Path  string
+	Count int
+}
+
+// SavedSearches is the Index sidebar; saving or deleting a search swaps in a
+// fresh copy.
+func SavedSearches(searches []SavedSearchView) g.Node {
+	return Aside(ID(savedSearchesID), Class("saved-searches"),
+		H3(g.Text("Saved searches")),
+		g.If(len(searches) == 0, P(Class("saved-search-empty"), g.Text("None yet."))),
+		Ul(g.Group(g.Map(searches, savedSearchEntry))), //nolint:unconvert
+		Div(Class("saved-search-save"),
+			Input(Type("text"), ID("savedSearchName"), Name("search_name"), Placeholder("Name current filters"), MaxLength("100")),
+			Button(Class(paginationClass),
+				Data("hx-post", searchesEndpoint),
+				Data("hx-include", includeSaveSearch),
+				Data("hx-target", "#"+savedSearchesID),
+				Data("hx-swap", "outerHTML"),
+				g.Text("Save"),
+			),
+		),
+		Div(Class("saved-search-share"),
+			A(Href(searchesExportEndpoint), g.Text("Export JSON")),
+			Form(Method("post"), Action(searchesImportEndpoint), EncType("multipart/form-data"),
+				Input(Type("file"), Name("file"), Accept(".json,application/json"), Required()),
+				Button(Type("submit"), Class(paginationClass), g.Text("Import")),
+			),
+		),
+	)
+}
+
+func savedSearchEntry(search SavedSearchView) g.Node {
+	return Li(
+		A(Href(search.Path), g.Text(search.Name)),
+		Span(Class("saved-search-count"), g.Text(" ("+strconv.Itoa(search.Count)+")")),
+		Button(Class("saved-search-delete"), Title("Delete "+search.Name),
+			Data("hx-post", searchesEndpoint),
+			Data("hx-vals", hxVals(map[string]string{"search_name": search.Name, "action": "delete"})),
+			Data("hx-target", "#"+savedSearchesID),
+			Data("hx-swap", "outerHTML"),
+			Data("hx-confirm", "Delete saved search "+search.Name+"?"),
+			g.Text("×"),
+		),
+	)
+}
@@ -0,0 +1,62 @@
+package templates
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components" //nolint:revive,staticcheck
+	. "maragu.dev/gomponents/html"       //nolint:revive,staticcheck
+)
+
+const setsEndpoint = "/sets"
+
+func Sets(sets []db.SetSummary, search string) g.Node {
+	return Layout("DDO Trove UI - Sets",
+		H1(g.Text("Set Bonus Tracker")),
+		Form(Class("filter-controls"), Method("get"), Action(setsEndpoint),
+			Div(Class("filter-row"),
+				Label(For("setSearch"), g.Text("Set name:")),
+				Input(Type("text"), ID("setSearch"), Name("search"), Value(search), Placeholder("Filter sets...")),
+				Button(Type("submit"), Class("pagination-button"), g.Text("Filter")),
+			),
+		),
+		P(Class("item-count"), g.Text(fmt.Sprintf("Found %d sets.", len(sets)))),
+		g.Group(g.Map(sets, setSummary)), //nolint:unconvert
+	)
+}
+
+func setSummary(set db.SetSummary) g.Node {
+	reached := make(map[int]bool)
+	for _, bonus := range set.Reached() {
+		reached[bonus.Pieces] = true
+	}
+
+	return Div(Class("item-list set-summary"),
+		H2(g.Text(set.Name)),
+		P(
+			g.Text(fmt.Sprintf("%d pieces owned, %d equippable together. ", len(set.Items), set.Equippable)),
+			A(Href("/?"+url.Values{"set_name": {set.Name}}.Encode()), g.Text("Show items")),
+		),
+		Ul(g.Group(g.Map(set.Bonuses, func(bonus db.SetBonus) g.Node { //nolint:unconvert
+			return Li(Classes{"set-bonus-reached": reached[bonus.Pieces]}, g.Text(bonus.Description))
+		}))),
+		Table(Class("data-table"),
+			THead(Tr(Th(g.Text("Holder")), Th(g.Text("Pieces")), Th(g.Text("Equippable together")))),
+			TBody(g.Group(g.Map(set.Holders, func(holding db.SetHolding) g.Node { //nolint:unconvert
+				names := make([]string, 0, len(holding.Items))
+				for _, item := range holding.Items {
+					names = append(names, item.Name)
+				}
+				return Tr(
+					Td(g.Text(holding.Holder)),
+					Td(g.Text(strings.Join(names, ", "))),
+					Td(g.Text(strconv.Itoa(holding.Equippable))),
+				)
+			}))),
+		),
+	)
+}
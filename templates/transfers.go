@@ -0,0 +1,67 @@
+package templates
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html" //nolint:revive,staticcheck
+)
+
+const transfersEndpoint = "/transfers"
+
+func Transfers(characters []db.CharacterInfo, suggestions []db.TransferSuggestion) g.Node {
+	return Layout("DDO Trove UI - Transfers",
+		H1(g.Text("Who Can Use This")),
+		P(g.Text("Gear that its holder cannot use, or that sits in a bank, and the characters that could equip it. "+
+			"Bound to character items are left out; bound to account items only go to characters of the same account.")),
+		P(Class("item-count"), g.Text(fmt.Sprintf("Found %d suggestions.", len(suggestions)))),
+		g.If(len(suggestions) > 0, Table(Class("data-table"),
+			THead(Tr(Th(g.Text("Item")), Th(g.Text("Level")), Th(g.Text("Binding")), Th(g.Text("Why move")), Th(g.Text("Could go to")))),
+			TBody(g.Group(g.Map(suggestions, transferRow))), //nolint:unconvert
+		)),
+		Div(Class("item-list"),
+			H2(g.Text("Characters")),
+			P(g.Text("Levels, proficiencies and armor types come from character profiles or, where unset, are inferred from equipped gear as lower bounds. Empty values do not restrict anything.")),
+			Table(Class("data-table"),
+				THead(Tr(Th(g.Text("Character")), Th(g.Text("Level")), Th(g.Text("Proficiencies")), Th(g.Text("Armor")))),
+				TBody(g.Group(g.Map(characters, func(character db.CharacterInfo) g.Node { //nolint:unconvert
+					return Tr(
+						Td(A(Href(profilesEndpoint+"?"+url.Values{"name": {character.Name}}.Encode()), g.Text(character.Name))),
+						Td(g.Text(levelText(character.Level))),
+						Td(g.Text(strings.Join(character.Proficiencies, ", "))),
+						Td(g.Text(strings.Join(character.ArmorTypes, ", "))),
+					)
+				}))),
+			),
+		),
+	)
+}
+
+func transferRow(suggestion db.TransferSuggestion) g.Node {
+	item := suggestion.Item
+	candidates := make([]g.Node, 0, len(suggestion.Candidates))
+	for index, name := range suggestion.Candidates {
+		if index > 0 {
+			candidates = append(candidates, g.Text(", "))
+		}
+		candidates = append(candidates, A(Href(characterPath(name)), g.Text(name)))
+	}
+	return Tr(
+		Td(Class("tooltip-cell"), itemNameDiv(item), itemTooltip(item)),
+		Td(g.Text(strconv.Itoa(item.MinimumLevel))),
+		Td(g.Text(item.BindingState().String())),
+		Td(g.Text(suggestion.Reason)),
+		Td(g.Group(candidates)),
+	)
+}
+
+func levelText(level int) string {
+	if level == 0 {
+		return "?"
+	}
+	return strconv.Itoa(level)
+}
@@ -0,0 +1,90 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components" //nolint:revive,staticcheck
+	. "maragu.dev/gomponents/html"       //nolint:revive,staticcheck
+)
+
+const upgradesEndpoint = "/upgrades"
+
+// UpgradeForm holds the upgrade finder inputs. Level 0 means no level is
+// known yet.
+type UpgradeForm struct {
+	Character string
+	Level     int
+	Levels    int
+	Stats     []string
+}
+
+func Upgrades(characters []string, form UpgradeForm, slots []db.UpgradeSlot) g.Node {
+	level := ""
+	if form.Level > 0 {
+		level = strconv.Itoa(form.Level)
+	}
+	return Layout("DDO Trove UI - Upgrades",
+		H1(g.Text("Level-up Upgrade Finder")),
+		Form(Class("filter-controls"), Method("get"), Action(upgradesEndpoint),
+			Div(Class("filter-row"),
+				Label(For("upgradeCharacter"), g.Text("Character:")),
+				Select(ID("upgradeCharacter"), Name("character"),
+					Option(Value(""), g.Text("None (use level)"), g.If(form.Character == "", Selected())),
+					g.Group(g.Map(characters, func(name string) g.Node { //nolint:unconvert
+						return selectedOption(name, form.Character)
+					})),
+				),
+				Label(For("upgradeLevel"), g.Text("Level:")),
+				Input(Type("number"), ID("upgradeLevel"), Name("level"), Value(level), Min("1"), Max("40"), Placeholder("from profile")),
+				Label(For("upgradeLevels"), g.Text("Next levels:")),
+				Input(Type("number"), ID("upgradeLevels"), Name("levels"), Value(strconv.Itoa(form.Levels)), Min("1"), Max("10")),
+			),
+			Div(Class("filter-row"),
+				Label(For("upgradeStats"), g.Text("Stats:")),
+				Input(Type("text"), ID("upgradeStats"), Name("stats"), Value(strings.Join(form.Stats, ", ")), Placeholder("Strength, Doublestrike")),
+				Button(Type("submit"), Class("pagination-button"), g.Text("Find upgrades")),
+			),
+		),
+		g.If(form.Level == 0, P(g.Text("Choose a character with a known level or enter a level."))),
+		g.If(form.Level > 0, P(Class("item-count"), g.Text(fmt.Sprintf("Items for levels %d to %d in %d slots.", form.Level+1, form.Level+form.Levels, len(slots))))),
+		g.Group(g.Map(slots, func(slot db.UpgradeSlot) g.Node { //nolint:unconvert
+			return upgradeSlot(slot, form.Stats)
+		})),
+	)
+}
+
+func upgradeSlot(slot db.UpgradeSlot, stats []string) g.Node {
+	headers := []g.Node{Th(g.Text("Item")), Th(g.Text("Level")), Th(g.Text("Holder"))}
+	current := []g.Node{Td(Em(g.Text("Best now"))), Td(), Td()}
+	for index, stat := range stats {
+		headers = append(headers, Th(g.Text(stat)))
+		current = append(current, Td(Em(g.Text(strconv.Itoa(slot.Current[index])))))
+	}
+	return Div(Class("item-list"),
+		H2(g.Text(slot.Slot)),
+		Table(Class("data-table"),
+			THead(Tr(headers...)),
+			TBody(
+				g.If(len(stats) > 0, Tr(current...)),
+				g.Group(g.Map(slot.Upgrades, upgradeRow)), //nolint:unconvert
+			),
+		),
+	)
+}
+
+func upgradeRow(upgrade db.Upgrade) g.Node {
+	item := upgrade.Item
+	cells := []g.Node{
+		Td(Class("tooltip-cell"), itemNameDiv(item), itemTooltip(item)),
+		Td(g.Text(strconv.Itoa(item.MinimumLevel))),
+		Td(A(Href(characterPath(item.CharacterName)), g.Text(item.CharacterName))),
+	}
+	for _, value := range upgrade.Stats {
+		cells = append(cells, Td(g.Text(strconv.Itoa(value))))
+	}
+	return Tr(Classes{"upgrade-better": upgrade.Better}, g.Group(cells))
+}
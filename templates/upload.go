@@ -0,0 +1,54 @@
+package templates
+
+import (
+	"fmt"
+	"sort"
+
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html" //nolint:revive,staticcheck
+)
+
+const (
+	uploadEndpoint = "/upload"
+	uploadResultID = "upload-result"
+)
+
+func Upload() g.Node {
+	return Layout("DDO Trove UI - Upload",
+		H1(g.Text("Upload Trove Data")),
+		P(g.Text("Upload one or more Trove JSON files, or a zip or tar.gz archive of a Trove folder. Files from the same source replace earlier uploads with the same name.")),
+		Form(Class("filter-controls upload-form"),
+			Data("hx-post", uploadEndpoint),
+			Data("hx-encoding", "multipart/form-data"),
+			Data("hx-target", "#"+uploadResultID),
+			Data("hx-swap", hxSwapMode),
+			Data("hx-on", "htmx:responseError: document.getElementById('"+uploadResultID+"').textContent = event.detail.xhr.responseText"),
+			Div(Class("filter-row"),
+				Label(For("uploadSource"), g.Text("Source:")),
+				Input(Type("text"), ID("uploadSource"), Name("source"), Placeholder("e.g. guildmate name"), Required()),
+				Label(For("uploadToken"), g.Text("Upload Token:")),
+				Input(Type("password"), ID("uploadToken"), Name("token"), Required()),
+			),
+			Div(Class("filter-row"),
+				Input(Type("file"), ID("uploadFiles"), Name("files"), Multiple(), Accept(".json,.zip,.tar.gz,.tgz"), Required()),
+				Button(Type("submit"), Class("pagination-button"), g.Text("Upload")),
+			),
+		),
+		Div(ID(uploadResultID)),
+	)
+}
+
+func UploadResult(source string, itemCounts map[string]int) g.Node {
+	names := make([]string, 0, len(itemCounts))
+	for name := range itemCounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return Div(Class("item-list"),
+		P(Class("item-count"), g.Text(fmt.Sprintf("Stored %d files for %s.", len(names), source))),
+		Ul(g.Group(g.Map(names, func(name string) g.Node { //nolint:unconvert
+			return Li(g.Text(fmt.Sprintf("%s: %d items", name, itemCounts[name])))
+		}))),
+	)
+}
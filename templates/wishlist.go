@@ -0,0 +1,86 @@
+package templates
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html" //nolint:revive,staticcheck
+)
+
+const wishListEndpoint = "/wishlist"
+
+func WishList(wishes []db.Wish) g.Node {
+	var owned, wanted []db.Wish
+	for _, wish := range wishes {
+		if wish.Fulfilled() {
+			owned = append(owned, wish)
+		} else {
+			wanted = append(wanted, wish)
+		}
+	}
+	return Layout("DDO Trove UI - Wish List",
+		H1(g.Text("Wish List")),
+		P(g.Text("Wishes are checked against all characters and banks whenever the data is reloaded.")),
+		Form(Class("filter-controls"), Method("post"), Action(wishListEndpoint),
+			Input(Type("hidden"), Name("action"), Value("add")),
+			Div(Class("filter-row"),
+				Label(For("wishQuery"), g.Text("Item name or search:")),
+				Input(Type("text"), ID("wishQuery"), Name("query"), Required(), MaxLength("100"), Placeholder("e.g. Sword of Shadow")),
+				Label(For("wishExact"), g.Text("Exact name:")),
+				Input(Type("checkbox"), ID("wishExact"), Name("exact"), Value("1"), Checked()),
+				Button(Type("submit"), Class("pagination-button"), g.Text("Add")),
+			),
+		),
+		g.If(len(owned) > 0, Div(Class("item-list wish-owned"),
+			H2(g.Text(fmt.Sprintf("You already own these (%d)", len(owned)))),
+			Table(Class("data-table"),
+				THead(Tr(Th(g.Text("Wish")), Th(g.Text("Found")), Th(g.Text("Where")), Th())),
+				TBody(g.Group(g.Map(owned, func(wish db.Wish) g.Node { //nolint:unconvert
+					return Tr(
+						Td(wishQuery(wish)),
+						Td(g.Text(wish.FulfilledAt.Local().Format(time.DateTime))),
+						Td(Ul(g.Group(g.Map(wish.Locations, func(location db.WishLocation) g.Node { //nolint:unconvert
+							return Li(
+								A(Href("/?"+url.Values{"name_search": {location.Name}}.Encode()), g.Text(location.Name)),
+								g.Text(" - "+location.Holder+", "+location.Container),
+							)
+						})))),
+						Td(removeWishForm(wish)),
+					)
+				}))),
+			),
+		)),
+		Div(Class("item-list"),
+			H2(g.Text(fmt.Sprintf("Still wanted (%d)", len(wanted)))),
+			g.If(len(wanted) > 0, Table(Class("data-table"),
+				THead(Tr(Th(g.Text("Wish")), Th(g.Text("Added")), Th())),
+				TBody(g.Group(g.Map(wanted, func(wish db.Wish) g.Node { //nolint:unconvert
+					return Tr(
+						Td(wishQuery(wish)),
+						Td(g.Text(wish.Added.Local().Format(time.DateTime))),
+						Td(removeWishForm(wish)),
+					)
+				}))),
+			)),
+		),
+	)
+}
+
+func wishQuery(wish db.Wish) g.Node {
+	if wish.Exact {
+		return g.Text(wish.Query)
+	}
+	return g.Group([]g.Node{g.Text(wish.Query), Span(Class("item-note"), g.Text(" (search)"))})
+}
+
+func removeWishForm(wish db.Wish) g.Node {
+	return Form(Method("post"), Action(wishListEndpoint),
+		Input(Type("hidden"), Name("action"), Value("remove")),
+		Input(Type("hidden"), Name("query"), Value(wish.Query)),
+		g.If(wish.Exact, Input(Type("hidden"), Name("exact"), Value("1"))),
+		Button(Type("submit"), Class("pagination-button"), g.Text("Remove")),
+	)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const transfersPath = "/transfers"
+
+func (a *App) handleTransfers(w http.ResponseWriter, r *http.Request) {
+	items, holders := a.visibleItemsAndHolders(r)
+	characters := a.characterInfo(items, holders)
+	suggestions := db.SuggestTransfers(items, characters)
+
+	if err := templates.Transfers(characters, suggestions).Render(w); err != nil {
+		slog.Error("render transfers failed", "err", err)
+		http.Error(w, "failed to render transfers", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const (
+	upgradesPath         = "/upgrades"
+	defaultUpgradeLevels = 3
+	maxUpgradeLevels     = 10
+)
+
+// handleUpgrades lists items that become equippable in the next few levels.
+// The level comes from the query or the chosen character's profile; with a
+// character, items are also narrowed to what it could receive and use by
+// the end of the level window.
+func (a *App) handleUpgrades(w http.ResponseWriter, r *http.Request) {
+	items, holders := a.visibleItemsAndHolders(r)
+	characters := a.characterInfo(items, holders)
+	names := make([]string, 0, len(characters))
+	for _, character := range characters {
+		names = append(names, character.Name)
+	}
+
+	query := r.URL.Query()
+	form := templates.UpgradeForm{
+		Character: query.Get("character"),
+		Levels:    defaultUpgradeLevels,
+		Stats:     splitList(query.Get("stats")),
+	}
+	if level, err := strconv.Atoi(query.Get("level")); err == nil && level > 0 {
+		form.Level = level
+	}
+	if levels, err := strconv.Atoi(query.Get("levels")); err == nil && levels > 0 {
+		form.Levels = min(levels, maxUpgradeLevels)
+	}
+	if character, found := findCharacter(characters, form.Character); found {
+		if form.Level == 0 {
+			form.Level = character.Level
+		}
+		// Upgrades are above the current level by definition, so usability
+		// is judged at the top of the level window.
+		if form.Level > 0 {
+			character.Level = form.Level + form.Levels
+		}
+		items = db.FilterUsableBy(items, character)
+	}
+
+	var slots []db.UpgradeSlot
+	if form.Level > 0 {
+		slots = db.FindUpgrades(items, form.Level, form.Levels, form.Stats)
+	}
+
+	if err := templates.Upgrades(names, form, slots).Render(w); err != nil {
+		slog.Error("render upgrades failed", "err", err)
+		http.Error(w, "failed to render upgrades", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const (
+	uploadPath         = "/upload"
+	uploadFormField    = "files"
+	uploadSourceField  = "source"
+	uploadTokenField   = "token"
+	maxUploadBytes     = 64 << 20
+	maxUploadMemory    = 8 << 20
+	uploadDirPerm      = 0o750
+	uploadSourceMaxLen = 64
+)
+
+var uploadSourcePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func (a *App) dataDirs() []string {
+	return dataDirs(a.cfg.Dirs, a.cfg.UploadDir)
+}
+
+// dataDirs returns the input directories followed by one directory per
+// upload source in the managed upload directory.
+func dataDirs(inputDirs []string, uploadDir string) []string {
+	dirs := append([]string(nil), inputDirs...)
+	if uploadDir == "" {
+		return dirs
+	}
+	entries, err := os.ReadDir(uploadDir)
+	if err != nil {
+		slog.Warn("failed to read upload directory", "path", uploadDir, "err", err)
+		return dirs
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, filepath.Join(uploadDir, entry.Name()))
+		}
+	}
+	return dirs
+}
+
+func (a *App) handleUpload(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if err := templates.Upload().Render(w); err != nil {
+			slog.Error("render upload failed", "err", err)
+			http.Error(w, "failed to render upload", http.StatusInternalServerError)
+		}
+	case http.MethodPost:
+		a.handleUploadPost(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *App) handleUploadPost(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
+	if err := r.ParseMultipartForm(maxUploadMemory); err != nil {
+		http.Error(w, "invalid upload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer func() {
+		if err := r.MultipartForm.RemoveAll(); err != nil {
+			slog.Warn("failed to remove multipart temp files", "err", err)
+		}
+	}()
+
+	if !a.uploadAuthorized(r) {
+		http.Error(w, "invalid upload token", http.StatusUnauthorized)
+		return
+	}
+
+	source := r.FormValue(uploadSourceField)
+	if len(source) > uploadSourceMaxLen || !uploadSourcePattern.MatchString(source) {
+		http.Error(w, "source must be 1-64 letters, digits, '-' or '_'", http.StatusBadRequest)
+		return
+	}
+
+	files, err := readUploadedFiles(r.MultipartForm.File[uploadFormField])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	counts, err := validateUploadedFiles(files)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = storeUploadedFiles(filepath.Join(a.cfg.UploadDir, source), files); err != nil {
+		slog.Error("failed to store uploaded files", "source", source, "err", err)
+		http.Error(w, "failed to store uploaded files", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("stored uploaded files", "source", source, "files", len(files))
+	a.monitorAndReloadItems()
+
+	if err = templates.UploadResult(source, counts).Render(w); err != nil {
+		slog.Error("render upload result failed", "err", err)
+		http.Error(w, "failed to render upload result", http.StatusInternalServerError)
+	}
+}
+
+func (a *App) uploadAuthorized(r *http.Request) bool {
+	token := r.FormValue(uploadTokenField)
+	return a.cfg.UploadToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.cfg.UploadToken)) == 1
+}
+
+func readUploadedFiles(headers []*multipart.FileHeader) (files []db.TroveFile, err error) {
+	if len(headers) == 0 {
+		return nil, errors.New("no files uploaded")
+	}
+	total := 0
+	for _, header := range headers {
+		headerFiles, readErr := readUploadedFile(header)
+		if readErr != nil {
+			return nil, fmt.Errorf("read %q: %w", header.Filename, readErr)
+		}
+		// Each archive is capped on its own; the upload as a whole gets the
+		// same budget.
+		for _, file := range headerFiles {
+			total += len(file.Data)
+		}
+		if total > db.MaxArchiveBytes || len(files)+len(headerFiles) > db.MaxArchiveFiles {
+			return nil, fmt.Errorf("%w: upload exceeds %d files or %d bytes uncompressed", db.ErrArchiveTooLarge, db.MaxArchiveFiles, db.MaxArchiveBytes)
+		}
+		files = append(files, headerFiles...)
+	}
+	return files, nil
+}
+
+func readUploadedFile(header *multipart.FileHeader) (files []db.TroveFile, err error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer file.Close()
+
+	name := filepath.Base(header.Filename)
+	switch {
+	case db.IsJSON(name):
+		data, readErr := io.ReadAll(file)
+		if readErr != nil {
+			return nil, fmt.Errorf("read: %w", readErr)
+		}
+		return []db.TroveFile{{Name: name, Data: data}}, nil
+	case db.IsZip(name):
+		files, err = db.ReadZip(file, header.Size)
+	case db.IsArchive(name):
+		files, err = db.ReadTarGz(file)
+	default:
+		return nil, errors.New("only .json, .zip, .tar.gz and .tgz files are accepted")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, errors.New("archive contains no JSON files")
+	}
+	return files, nil
+}
+
+// validateUploadedFiles decodes every file before anything is stored, so a
+// single bad file rejects the whole upload. It returns item counts per file.
+func validateUploadedFiles(files []db.TroveFile) (counts map[string]int, err error) {
+	counts = make(map[string]int, len(files))
+	for _, file := range files {
+		if _, exists := counts[file.Name]; exists {
+			return nil, fmt.Errorf("duplicate file name %q", file.Name)
+		}
+		items, decodeErr := db.DecodeItems(file.Data)
+		if decodeErr != nil {
+			return nil, fmt.Errorf("invalid Trove file %q: %w", file.Name, decodeErr)
+		}
+		counts[file.Name] = len(items)
+	}
+	return counts, nil
+}
+
+func storeUploadedFiles(dir string, files []db.TroveFile) (err error) {
+	if err = os.MkdirAll(dir, uploadDirPerm); err != nil {
+		return fmt.Errorf("create directory %q: %w", dir, err)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	for _, file := range files {
+		if err = writeFileAtomic(filepath.Join(dir, file.Name), file.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
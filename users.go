@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const (
+	loginPath          = "/login"
+	logoutPath         = "/logout"
+	sessionCookieName  = "ddo_trove_session"
+	sessionTTL         = 7 * 24 * time.Hour
+	sessionTokenBytes  = 32
+	passwordHashScheme = "pbkdf2-sha256"
+	passwordIterations = 600000
+	passwordSaltBytes  = 16
+	passwordKeyBytes   = 32
+	passwordHashParts  = 4
+)
+
+type userContextKey struct{}
+
+// dummyPasswordHash is checked for unknown user names, so that a login takes
+// as long whether or not the user exists. No password derives an all-zero key.
+var dummyPasswordHash = strings.Join([]string{
+	passwordHashScheme,
+	strconv.Itoa(passwordIterations),
+	base64.RawStdEncoding.EncodeToString(make([]byte, passwordSaltBytes)),
+	base64.RawStdEncoding.EncodeToString(make([]byte, passwordKeyBytes)),
+}, "$")
+
+// User is one entry of the users file. Non-admin users only see items loaded
+// from Dirs (directories or archives, including subdirectories) or belonging
+// to one of Accounts (SubscriptionAlias).
+type User struct {
+	Name         string   `json:"name"`
+	PasswordHash string   `json:"password_hash"`
+	Admin        bool     `json:"admin,omitempty"`
+	Dirs         []string `json:"dirs,omitempty"`
+	Accounts     []string `json:"accounts,omitempty"`
+}
+
+type session struct {
+	userName string
+	expires  time.Time
+}
+
+type UserStore struct {
+	users map[string]*User
+
+	mu       sync.Mutex
+	sessions map[string]session
+}
+
+type HashPasswordCmd struct{}
+
+func (c HashPasswordCmd) Run() error {
+	return hashPasswordFrom(os.Stdin, os.Stdout)
+}
+
+func hashPasswordFrom(input io.Reader, output io.Writer) (err error) {
+	password, err := bufio.NewReader(input).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read password: %w", err)
+	}
+	password = strings.TrimRight(password, "\r\n")
+	if password == "" {
+		return errors.New("empty password")
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintln(output, hash); err != nil {
+		return fmt.Errorf("write hash: %w", err)
+	}
+	return nil
+}
+
+func hashPassword(password string) (encoded string, err error) {
+	salt := make([]byte, passwordSaltBytes)
+	if _, err = rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordIterations, passwordKeyBytes)
+	if err != nil {
+		return "", fmt.Errorf("derive key: %w", err)
+	}
+	return strings.Join([]string{
+		passwordHashScheme,
+		strconv.Itoa(passwordIterations),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	}, "$"), nil
+}
+
+func verifyPassword(encoded, password string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != passwordHashParts || parts[0] != passwordHashScheme {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(expected))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(key, expected) == 1
+}
+
+func loadUserStore(path string) (store *UserStore, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read users file %q: %w", path, err)
+	}
+	var users []*User
+	if err = json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("parse users file %q: %w", path, err)
+	}
+	return newUserStore(users)
+}
+
+func newUserStore(users []*User) (store *UserStore, err error) {
+	store = &UserStore{users: make(map[string]*User, len(users)), sessions: make(map[string]session)}
+	for _, user := range users {
+		if user.Name == "" || user.PasswordHash == "" {
+			return nil, errors.New("every user needs a name and a password hash")
+		}
+		if _, exists := store.users[user.Name]; exists {
+			return nil, fmt.Errorf("duplicate user %q", user.Name)
+		}
+		for index, dir := range user.Dirs {
+			absDir, absErr := filepath.Abs(dir)
+			if absErr != nil {
+				return nil, fmt.Errorf("resolve directory %q of user %q: %w", dir, user.Name, absErr)
+			}
+			user.Dirs[index] = absDir
+		}
+		store.users[user.Name] = user
+	}
+	return store, nil
+}
+
+func (s *UserStore) authenticate(name, password string) *User {
+	user, exists := s.users[name]
+	if !exists {
+		verifyPassword(dummyPasswordHash, password)
+		return nil
+	}
+	if !verifyPassword(user.PasswordHash, password) {
+		return nil
+	}
+	return user
+}
+
+func (s *UserStore) createSession(user *User) (token string, err error) {
+	raw := make([]byte, sessionTokenBytes)
+	if _, err = rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate session token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(raw)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for existing, value := range s.sessions {
+		if now.After(value.expires) {
+			delete(s.sessions, existing)
+		}
+	}
+	s.sessions[token] = session{userName: user.Name, expires: now.Add(sessionTTL)}
+	return token, nil
+}
+
+func (s *UserStore) sessionUser(token string) *User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, exists := s.sessions[token]
+	if !exists {
+		return nil
+	}
+	if time.Now().After(value.expires) {
+		delete(s.sessions, token)
+		return nil
+	}
+	return s.users[value.userName]
+}
+
+func (s *UserStore) deleteSession(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, token)
+}
+
+func (u *User) canSee(item db.Item) bool {
+	if u.Admin {
+		return true
+	}
+	for _, dir := range u.Dirs {
+		if item.SourcePath == dir || strings.HasPrefix(item.SourcePath, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	for _, account := range u.Accounts {
+		if item.SubscriptionAlias == account {
+			return true
+		}
+	}
+	return false
+}
+
+// itemsVisibleTo returns the items the named user may see, for work done
+// outside a request. Without user accounts the empty name sees everything;
+// unknown users see nothing.
+func (a *App) itemsVisibleTo(name string, items []db.Item) []db.Item {
+	if a.users == nil {
+		return items
+	}
+	user, exists := a.users.users[name]
+	if !exists {
+		return nil
+	}
+	var visible []db.Item
+	for _, item := range items {
+		if user.canSee(item) {
+			visible = append(visible, item)
+		}
+	}
+	return visible
+}
+
+func userFromRequest(r *http.Request) *User {
+	user, _ := r.Context().Value(userContextKey{}).(*User)
+	return user
+}
+
+// scopedUser returns the request's user when it may only see part of the
+// items, and nil when it sees everything.
+func scopedUser(r *http.Request) *User {
+	user := userFromRequest(r)
+	if user == nil || user.Admin {
+		return nil
+	}
+	return user
+}
+
+// visibleItemsAndHolders returns the annotated items the request's user may
+// see and their holders. Handlers read the trove only through this, so that
+// locking and per-user scoping live in one place.
+func (a *App) visibleItemsAndHolders(r *http.Request) ([]db.Item, []db.Holder) {
+	a.mu.RLock()
+	items := a.allItems.Items
+	holders := a.allItems.Holders
+	a.mu.RUnlock()
+	if user := scopedUser(r); user != nil {
+		var visible []db.Item
+		for _, item := range items {
+			if user.canSee(item) {
+				visible = append(visible, item)
+			}
+		}
+		items = visible
+		holders = holdersOf(items, holders)
+	}
+	return a.annotate(items), holders
+}
+
+func (a *App) requireLogin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == loginPath || strings.HasPrefix(r.URL.Path, staticPathPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		var user *User
+		if cookie, err := r.Cookie(sessionCookieName); err == nil {
+			user = a.users.sessionUser(cookie.Value)
+		}
+		if user == nil {
+			if r.Header.Get("HX-Request") != "" {
+				w.Header().Set("HX-Redirect", loginPath)
+				http.Error(w, "login required", http.StatusUnauthorized)
+				return
+			}
+			http.Redirect(w, r, loginPath, http.StatusSeeOther)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userContextKey{}, user)))
+	})
+}
+
+func (a *App) handleLogin(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		a.renderLogin(w, "", http.StatusOK)
+	case http.MethodPost:
+		user := a.users.authenticate(r.PostFormValue("username"), r.PostFormValue("password"))
+		if user == nil {
+			slog.Warn("failed login", "username", r.PostFormValue("username"), "remote", r.RemoteAddr)
+			a.renderLogin(w, "Invalid username or password.", http.StatusUnauthorized)
+			return
+		}
+		token, err := a.users.createSession(user)
+		if err != nil {
+			slog.Error("create session failed", "err", err)
+			http.Error(w, "failed to create session", http.StatusInternalServerError)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:     sessionCookieName,
+			Value:    token,
+			Path:     "/",
+			MaxAge:   int(sessionTTL.Seconds()),
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+		slog.Info("login", "username", user.Name)
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *App) renderLogin(w http.ResponseWriter, message string, status int) {
+	w.WriteHeader(status)
+	if err := templates.Login(message).Render(w); err != nil {
+		slog.Error("render login failed", "err", err)
+	}
+}
+
+func (a *App) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		a.users.deleteSession(cookie.Value)
+	}
+	http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: "", Path: "/", MaxAge: -1, HttpOnly: true})
+	http.Redirect(w, r, loginPath, http.StatusSeeOther)
+}
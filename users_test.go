@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"gotest.tools/v3/assert"
+)
+
+func TestHashPassword(t *testing.T) {
+	var output bytes.Buffer
+	assert.NilError(t, hashPasswordFrom(strings.NewReader("hunter2\n"), &output))
+
+	encoded := strings.TrimSpace(output.String())
+	assert.Assert(t, strings.HasPrefix(encoded, passwordHashScheme+"$"))
+	assert.Assert(t, verifyPassword(encoded, "hunter2"))
+	assert.Assert(t, !verifyPassword(encoded, "hunter3"))
+	assert.Assert(t, !verifyPassword("plain", "plain"))
+	// The dummy hash must parse, or unknown users would fail fast.
+	assert.Equal(t, len(strings.Split(dummyPasswordHash, "$")), passwordHashParts)
+	assert.Assert(t, strings.HasPrefix(dummyPasswordHash, passwordHashScheme+"$"+strconv.Itoa(passwordIterations)+"$"))
+
+	assert.Assert(t, hashPasswordFrom(strings.NewReader("\n"), &output) != nil)
+}
+
+func TestUserScopes(t *testing.T) {
+	hash, err := hashPassword("secret")
+	assert.NilError(t, err)
+	store, err := newUserStore([]*User{
+		{Name: "admin", PasswordHash: hash, Admin: true},
+		{Name: "alice", PasswordHash: hash, Dirs: []string{"/data/alice"}},
+		{Name: "bob", PasswordHash: hash, Accounts: []string{"BobMain"}},
+	})
+	assert.NilError(t, err)
+
+	items := []db.Item{
+		{Name: "Alice Sword", ItemType: "Weapon", CharacterName: "AliceChar", SourcePath: "/data/alice/Trove"},
+		{Name: "Bob Ring", ItemType: "Accessory", CharacterName: "BobChar", SubscriptionAlias: "BobMain", SourcePath: "/data/bob"},
+		{Name: "Shared Cloak", ItemType: "Armor", CharacterName: "Other", SourcePath: "/data/alicia"},
+	}
+	app := newTestApp(t, items)
+	app.users = store
+	handler := app.routes()
+
+	login := func(t *testing.T, name, password string) *httptest.ResponseRecorder {
+		t.Helper()
+		form := url.Values{"username": {name}, "password": {password}}
+		request := httptest.NewRequest(http.MethodPost, loginPath, strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("anonymous redirected", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, recorder.Code, http.StatusSeeOther)
+		assert.Equal(t, recorder.Header().Get("Location"), loginPath)
+	})
+
+	t.Run("bad password", func(t *testing.T) {
+		assert.Equal(t, login(t, "alice", "wrong").Code, http.StatusUnauthorized)
+	})
+
+	testCases := []struct {
+		user     string
+		visible  []string
+		excluded []string
+	}{
+		{user: "admin", visible: []string{"Alice Sword", "Bob Ring", "Shared Cloak"}},
+		{user: "alice", visible: []string{"Alice Sword"}, excluded: []string{"Bob Ring", "Shared Cloak", "BobChar"}},
+		{user: "bob", visible: []string{"Bob Ring"}, excluded: []string{"Alice Sword", "Shared Cloak", "AliceChar"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.user, func(t *testing.T) {
+			recorder := login(t, testCase.user, "secret")
+			assert.Equal(t, recorder.Code, http.StatusSeeOther)
+			result := recorder.Result()
+			defer result.Body.Close()
+			cookies := result.Cookies()
+			assert.Equal(t, len(cookies), 1)
+
+			for _, path := range []string{"/", "/items"} {
+				request := httptest.NewRequest(http.MethodGet, path, nil)
+				request.AddCookie(cookies[0])
+				pageRecorder := httptest.NewRecorder()
+				handler.ServeHTTP(pageRecorder, request)
+				assert.Equal(t, pageRecorder.Code, http.StatusOK)
+				body := pageRecorder.Body.String()
+				for _, name := range testCase.visible {
+					assert.Assert(t, strings.Contains(body, name), "%s should see %s on %s", testCase.user, name, path)
+				}
+				for _, name := range testCase.excluded {
+					assert.Assert(t, !strings.Contains(body, name), "%s should not see %s on %s", testCase.user, name, path)
+				}
+			}
+		})
+	}
+}
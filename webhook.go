@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/fingon/ddo-trove-ui/db"
+)
+
+const (
+	webhookEvent          = "trove.changed"
+	webhookAttempts       = 4
+	webhookInitialBackoff = 2 * time.Second
+	webhookTimeout        = 10 * time.Second
+	defaultFullPercent    = 90
+)
+
+var errWebhookRejected = errors.New("webhook rejected the payload")
+
+// webhookPayload is the JSON body POSTed to each webhook after a reload that
+// changed something. Lists are always present, possibly empty:
+//
+//	{
+//	  "event": "trove.changed",
+//	  "time": "2026-10-18T12:00:00Z",
+//	  "added": [{"name": "Sword", "identity": "Server/123", "holder": "CharA",
+//	             "location": "Inventory, row 1 col 2", "quantity": 1, "tags": ["guild loan"]}],
+//	  "wishes": [{"owner": "alice", "query": "Sword", "exact": true, "fulfilled_at": "...",
+//	              "locations": [{"identity": "Server/123", "name": "Sword", "holder": "CharA", "container": "Inventory"}]}],
+//	  "nearly_full": [{"holder": "Account (Shared Bank)", "used": 95, "max": 100}]
+//	}
+type webhookPayload struct {
+	Event      string            `json:"event"`
+	Time       time.Time         `json:"time"`
+	Added      []webhookItem     `json:"added"`
+	Wishes     []ownedWish       `json:"wishes"`
+	NearlyFull []webhookCapacity `json:"nearly_full"`
+}
+
+// webhookItem is an added item; tags let a bot pick out e.g. returned
+// guild loans.
+type webhookItem struct {
+	diffEntry
+	Tags []string `json:"tags,omitempty"`
+}
+
+type webhookCapacity struct {
+	Holder string `json:"holder"`
+	Server string `json:"server"`
+	Used   int    `json:"used"`
+	Max    int    `json:"max"`
+}
+
+// newWebhookPayload collects the changes between two loads worth announcing.
+// Holders are nearly full once their usage reaches fullPercent, and are only
+// reported when they cross it. The payload is false when nothing happened.
+func (a *App) newWebhookPayload(before, after *db.AllItems, fulfilled []ownedWish, now time.Time) (webhookPayload, bool) {
+	payload := webhookPayload{
+		Event:      webhookEvent,
+		Time:       now.UTC(),
+		Added:      []webhookItem{},
+		Wishes:     fulfilled,
+		NearlyFull: []webhookCapacity{},
+	}
+	if payload.Wishes == nil {
+		payload.Wishes = []ownedWish{}
+	}
+	for _, item := range a.annotate(db.DiffItems(before.Items, after.Items).Gained) {
+		payload.Added = append(payload.Added, webhookItem{diffEntry: newDiffEntry(item), Tags: item.Annotation.Tags})
+	}
+
+	fullPercent := a.cfg.WebhookFullPercent
+	if fullPercent == 0 {
+		fullPercent = defaultFullPercent
+	}
+	nearlyFull := func(holder db.Holder) bool {
+		return holder.MaxCapacity > 0 && holder.UsedCapacity*100 >= holder.MaxCapacity*fullPercent
+	}
+	// Names repeat across servers, so holders are told apart by both.
+	type holderKey struct{ server, name string }
+	wasFull := make(map[holderKey]bool)
+	for _, holder := range before.Holders {
+		key := holderKey{server: holder.Server, name: holder.Name}
+		wasFull[key] = wasFull[key] || nearlyFull(holder)
+	}
+	for _, holder := range after.Holders {
+		if nearlyFull(holder) && !wasFull[holderKey{server: holder.Server, name: holder.Name}] {
+			payload.NearlyFull = append(payload.NearlyFull, webhookCapacity{Holder: holder.Name, Server: holder.Server, Used: holder.UsedCapacity, Max: holder.MaxCapacity})
+		}
+	}
+	return payload, len(payload.Added) > 0 || len(payload.Wishes) > 0 || len(payload.NearlyFull) > 0
+}
+
+// webhookNotifier delivers payloads to the configured URLs, retrying failed
+// deliveries with exponential backoff.
+type webhookNotifier struct {
+	urls     []string
+	client   *http.Client
+	attempts int
+	backoff  time.Duration
+}
+
+func newWebhookNotifier(urls []string) *webhookNotifier {
+	return &webhookNotifier{
+		urls:     urls,
+		client:   &http.Client{Timeout: webhookTimeout},
+		attempts: webhookAttempts,
+		backoff:  webhookInitialBackoff,
+	}
+}
+
+// notify posts payload to every URL, returning the errors of the URLs that
+// never accepted it.
+func (n *webhookNotifier) notify(ctx context.Context, payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+	var errs []error
+	for _, url := range n.urls {
+		if err = n.deliver(ctx, url, body); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", url, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *webhookNotifier) deliver(ctx context.Context, url string, body []byte) (err error) {
+	backoff := n.backoff
+	for attempt := 1; attempt <= n.attempts; attempt++ {
+		var retry bool
+		if retry, err = n.post(ctx, url, body); err == nil || !retry {
+			return err
+		}
+		if attempt == n.attempts {
+			break
+		}
+		slog.Warn("webhook delivery failed, retrying", "url", url, "attempt", attempt, "err", err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("give up after %d attempts: %w", attempt, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("give up after %d attempts: %w", n.attempts, err)
+}
+
+// post sends one attempt; retry tells whether the failure may be temporary.
+func (n *webhookNotifier) post(ctx context.Context, url string, body []byte) (retry bool, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("create request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := n.client.Do(request)
+	if err != nil {
+		return true, fmt.Errorf("post: %w", err)
+	}
+	_ = response.Body.Close()
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		return false, nil
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+		return true, fmt.Errorf("status %d", response.StatusCode)
+	default:
+		return false, fmt.Errorf("%w: status %d", errWebhookRejected, response.StatusCode)
+	}
+}
+
+// announceChanges sends the webhook payload for a reload in the background,
+// so that slow webhooks do not hold up the next reload.
+func (a *App) announceChanges(before, after *db.AllItems, fulfilled []ownedWish) {
+	if a.webhooks == nil {
+		return
+	}
+	payload, changed := a.newWebhookPayload(before, after, fulfilled, time.Now())
+	if !changed {
+		return
+	}
+	go func() {
+		if err := a.webhooks.notify(context.Background(), payload); err != nil {
+			slog.Error("webhook notification failed", "err", err)
+		}
+	}()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"gotest.tools/v3/assert"
+)
+
+func newTestNotifier(url string) *webhookNotifier {
+	notifier := newWebhookNotifier([]string{url})
+	notifier.backoff = time.Millisecond
+	return notifier
+}
+
+func TestWebhookRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Header.Get("Content-Type"), "application/json")
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	assert.NilError(t, newTestNotifier(server.URL).notify(context.Background(), webhookPayload{Event: webhookEvent}))
+	assert.Equal(t, calls.Load(), int32(3))
+
+	calls.Store(-10)
+	err := newTestNotifier(server.URL).notify(context.Background(), webhookPayload{Event: webhookEvent})
+	assert.ErrorContains(t, err, "give up after 4 attempts")
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+	calls.Store(0)
+	err = newTestNotifier(rejecting.URL).notify(context.Background(), webhookPayload{Event: webhookEvent})
+	assert.ErrorIs(t, err, errWebhookRejected)
+	assert.Equal(t, calls.Load(), int32(1))
+}
+
+func TestWebhookOnReload(t *testing.T) {
+	received := make(chan map[string]any, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received <- payload
+	}))
+	defer server.Close()
+
+	items := []db.Item{{Name: "Old Boots", CharacterName: "CharA", Container: "Inventory", ItemID: 1, Server: "Argonnessen"}}
+	app := newTestApp(t, items)
+	app.allItems.Holders = []db.Holder{{Name: "CharA", Server: "Argonnessen", UsedCapacity: 10, MaxCapacity: 100}}
+	app.webhooks = newTestNotifier(server.URL)
+	_, err := app.updateWishes("", nil, func([]db.Wish) []db.Wish {
+		return []db.Wish{{Query: "Loaned Sword", Exact: true}}
+	})
+	assert.NilError(t, err)
+	_, err = app.updateAnnotation("Argonnessen/2", func(db.Annotation) db.Annotation {
+		return db.Annotation{Tags: []string{"guild loan"}}
+	})
+	assert.NilError(t, err)
+
+	dir := t.TempDir()
+	trove := `{"Name":"CharA","Server":"Argonnessen","UsedCapacity":95,"MaxCapacity":100,"Inventory":[
+		{"Name":"Old Boots","ItemId":1,"Container":"Inventory"},
+		{"Name":"Loaned Sword","ItemId":2,"Container":"Inventory","Quantity":1}]}`
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "CharA.json"), []byte(trove), 0o600))
+	app.cfg.Dirs = []string{dir}
+	app.monitorAndReloadItems()
+
+	select {
+	case payload := <-received:
+		assert.Equal(t, payload["event"], webhookEvent)
+		added := payload["added"].([]any)
+		assert.Equal(t, len(added), 1)
+		sword := added[0].(map[string]any)
+		assert.Equal(t, sword["name"], "Loaned Sword")
+		assert.Equal(t, sword["holder"], "CharA")
+		assert.DeepEqual(t, sword["tags"], []any{"guild loan"})
+		assert.Equal(t, len(payload["wishes"].([]any)), 1)
+		full := payload["nearly_full"].([]any)
+		assert.Equal(t, len(full), 1)
+		assert.Equal(t, full[0].(map[string]any)["used"], float64(95))
+		assert.Equal(t, full[0].(map[string]any)["server"], "Argonnessen")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no webhook call")
+	}
+}
+
+func TestWebhookNearlyFullPerServer(t *testing.T) {
+	app := newTestApp(t, nil)
+	holder := func(server string, used int) db.Holder {
+		return db.Holder{Name: "CharA", Server: server, UsedCapacity: used, MaxCapacity: 100}
+	}
+	before := &db.AllItems{Holders: []db.Holder{holder("Khyber", 95), holder("Argonnessen", 10)}}
+	after := &db.AllItems{Holders: []db.Holder{holder("Argonnessen", 95), holder("Khyber", 96)}}
+
+	payload, send := app.newWebhookPayload(before, after, nil, time.Now())
+	assert.Assert(t, send)
+	assert.DeepEqual(t, payload.NearlyFull, []webhookCapacity{{Holder: "CharA", Server: "Argonnessen", Used: 95, Max: 100}})
+}
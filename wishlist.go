@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"github.com/fingon/ddo-trove-ui/templates"
+)
+
+const (
+	wishListPath         = "/wishlist"
+	wishListStateFile    = "wish-list.json"
+	maxWishQueryLength   = 100
+	wishListActionAdd    = "add"
+	wishListActionRemove = "remove"
+)
+
+// wishListState keeps each user's wish list; without user accounts
+// everything is stored under the empty name.
+type wishListState struct {
+	Users map[string][]db.Wish `json:"users"`
+}
+
+// ownedWish is a wish together with the user who made it.
+type ownedWish struct {
+	Owner string `json:"owner,omitempty"`
+	db.Wish
+}
+
+func (a *App) loadWishes(owner string) (wishes []db.Wish, err error) {
+	var state wishListState
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+	if err = loadState(a.statePath(wishListStateFile), &state); err != nil {
+		return nil, err
+	}
+	return state.Users[owner], nil
+}
+
+// updateWishes applies update to the owner's wish list, evaluates the result
+// against items and stores it. The wishes fulfilled by this evaluation are
+// returned.
+func (a *App) updateWishes(owner string, items []db.Item, update func([]db.Wish) []db.Wish) (fulfilled []db.Wish, err error) {
+	path := a.statePath(wishListStateFile)
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+	var state wishListState
+	if err = loadState(path, &state); err != nil {
+		return nil, err
+	}
+	if state.Users == nil {
+		state.Users = make(map[string][]db.Wish)
+	}
+	wishes := update(state.Users[owner])
+	if len(wishes) == 0 && len(state.Users[owner]) == 0 {
+		return nil, nil
+	}
+	state.Users[owner], fulfilled = db.EvaluateWishes(wishes, items, time.Now())
+	if len(state.Users[owner]) == 0 {
+		delete(state.Users, owner)
+	}
+	return fulfilled, saveState(path, state)
+}
+
+// checkWishes evaluates every user's wish list after a reload, against the
+// items that user can see.
+func (a *App) checkWishes(items []db.Item) []ownedWish {
+	a.stateMu.Lock()
+	var state wishListState
+	err := loadState(a.statePath(wishListStateFile), &state)
+	a.stateMu.Unlock()
+	if err != nil {
+		slog.Error("load wish lists failed", "err", err)
+		return nil
+	}
+	owners := make([]string, 0, len(state.Users))
+	for owner := range state.Users {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	var result []ownedWish
+	for _, owner := range owners {
+		fulfilled, err := a.updateWishes(owner, a.itemsVisibleTo(owner, items), func(wishes []db.Wish) []db.Wish { return wishes })
+		if err != nil {
+			slog.Error("evaluate wish list failed", "owner", owner, "err", err)
+			continue
+		}
+		for _, wish := range fulfilled {
+			slog.Info("wish list entry fulfilled", "owner", owner, "query", wish.Query, "holder", wish.Locations[0].Holder, "matches", len(wish.Locations))
+			result = append(result, ownedWish{Owner: owner, Wish: wish})
+		}
+	}
+	return result
+}
+
+func sameWish(wish db.Wish, query string, exact bool) bool {
+	return wish.Exact == exact && strings.EqualFold(wish.Query, query)
+}
+
+// handleWishList shows the wish list; a POST adds or removes an entry and
+// checks the list against the current items right away.
+func (a *App) handleWishList(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		a.renderWishList(w, r)
+	case http.MethodPost:
+		query := strings.TrimSpace(r.PostFormValue("query"))
+		exact := r.PostFormValue("exact") != ""
+		if query == "" || len(query) > maxWishQueryLength {
+			http.Error(w, fmt.Sprintf("wish must be 1 to %d characters", maxWishQueryLength), http.StatusBadRequest)
+			return
+		}
+		var update func([]db.Wish) []db.Wish
+		switch r.PostFormValue("action") {
+		case wishListActionAdd:
+			update = func(wishes []db.Wish) []db.Wish {
+				for _, wish := range wishes {
+					if sameWish(wish, query, exact) {
+						return wishes
+					}
+				}
+				return append(wishes, db.Wish{Query: query, Exact: exact, Added: time.Now()})
+			}
+		case wishListActionRemove:
+			update = func(wishes []db.Wish) []db.Wish {
+				var kept []db.Wish
+				for _, wish := range wishes {
+					if !sameWish(wish, query, exact) {
+						kept = append(kept, wish)
+					}
+				}
+				return kept
+			}
+		default:
+			http.Error(w, "unknown action", http.StatusBadRequest)
+			return
+		}
+		items, _ := a.visibleItemsAndHolders(r)
+		if _, err := a.updateWishes(searchOwner(r), items, update); err != nil {
+			slog.Error("save wish list failed", "err", err)
+			http.Error(w, "failed to save wish list", http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, wishListPath, http.StatusSeeOther)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *App) renderWishList(w http.ResponseWriter, r *http.Request) {
+	wishes, err := a.loadWishes(searchOwner(r))
+	if err != nil {
+		slog.Error("load wish list failed", "err", err)
+		http.Error(w, "failed to load wish list", http.StatusInternalServerError)
+		return
+	}
+	if scopedUser(r) != nil {
+		visible, _ := a.visibleItemsAndHolders(r)
+		wishes = visibleWishes(wishes, visible)
+	}
+	if err = templates.WishList(wishes).Render(w); err != nil {
+		slog.Error("render wish list failed", "err", err)
+		http.Error(w, "failed to render wish list", http.StatusInternalServerError)
+	}
+}
+
+// visibleWishes drops locations of items the user can no longer see, e.g.
+// after their access changed; a wish only found there shows as still wanted.
+func visibleWishes(wishes []db.Wish, items []db.Item) []db.Wish {
+	visible := make(map[db.WishLocation]bool, len(items))
+	for _, item := range items {
+		visible[db.WishLocation{Identity: item.Identity(), Name: item.Name, Holder: item.CharacterName, Container: item.Container}] = true
+	}
+	result := make([]db.Wish, 0, len(wishes))
+	for _, wish := range wishes {
+		var locations []db.WishLocation
+		for _, location := range wish.Locations {
+			if visible[location] {
+				locations = append(locations, location)
+			}
+		}
+		wish.Locations = locations
+		if len(locations) == 0 {
+			wish.FulfilledAt = time.Time{}
+		}
+		result = append(result, wish)
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fingon/ddo-trove-ui/db"
+	"gotest.tools/v3/assert"
+)
+
+func TestWishList(t *testing.T) {
+	items := []db.Item{{Name: "Sword of Shadow", CharacterName: "CharA", Container: "Inventory", MinimumLevel: 12}}
+	app := newTestApp(t, items)
+	handler := app.routes()
+
+	post := func(form url.Values) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest("POST", "/wishlist", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	assert.Equal(t, post(url.Values{"action": {"add"}, "query": {"sword of shadow"}, "exact": {"1"}}).Code, 303)
+	assert.Equal(t, post(url.Values{"action": {"add"}, "query": {"Greensteel Goggles"}, "exact": {"1"}}).Code, 303)
+	assert.Equal(t, post(url.Values{"action": {"add"}, "query": {"Greensteel Goggles"}, "exact": {"1"}}).Code, 303)
+	assert.Equal(t, post(url.Values{"action": {"add"}, "query": {" "}}).Code, 400)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/wishlist", nil))
+	assert.Equal(t, recorder.Code, 200)
+	body := recorder.Body.String()
+	assert.Assert(t, strings.Contains(body, "You already own these (1)"))
+	assert.Assert(t, strings.Contains(body, " - CharA, Inventory"))
+	assert.Assert(t, strings.Contains(body, "Still wanted (1)"))
+
+	// A reload that brings in the goggles fulfills the second wish.
+	dir := t.TempDir()
+	trove := `{"Name":"CharB","Server":"Khyber","Inventory":[{"ItemId":7,"Name":"Greensteel Goggles","ItemType":"Armor","Container":"Inventory"}]}`
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "CharB.json"), []byte(trove), 0o600))
+	app.cfg.Dirs = []string{dir}
+	app.monitorAndReloadItems()
+	wishes, err := app.loadWishes("")
+	assert.NilError(t, err)
+	assert.Equal(t, len(wishes), 2)
+	assert.Assert(t, wishes[1].Fulfilled())
+	assert.DeepEqual(t, wishes[1].Locations, []db.WishLocation{{Identity: "Khyber/7", Name: "Greensteel Goggles", Holder: "CharB", Container: "Inventory"}})
+	// The sword is gone after the reload but stays fulfilled.
+	assert.Assert(t, wishes[0].Fulfilled())
+
+	assert.Equal(t, post(url.Values{"action": {"remove"}, "query": {"Sword of Shadow"}, "exact": {"1"}}).Code, 303)
+	wishes, err = app.loadWishes("")
+	assert.NilError(t, err)
+	assert.Equal(t, len(wishes), 1)
+}
+
+func TestWishListPerUser(t *testing.T) {
+	hash, err := hashPassword("secret")
+	assert.NilError(t, err)
+	store, err := newUserStore([]*User{
+		{Name: "alice", PasswordHash: hash, Accounts: []string{"AliceMain"}},
+		{Name: "bob", PasswordHash: hash, Accounts: []string{"BobMain"}},
+	})
+	assert.NilError(t, err)
+	items := []db.Item{{Name: "Cloak", Server: "Khyber", ItemID: 1, CharacterName: "Account (Shared Bank)", Container: "SharedBank", SubscriptionAlias: "AliceMain"}}
+	app := newTestApp(t, items)
+	app.users = store
+	handler := app.routes()
+
+	wishFor := func([]db.Wish) []db.Wish { return []db.Wish{{Query: "Cloak", Exact: true}} }
+	for _, owner := range []string{"alice", "bob"} {
+		_, err = app.updateWishes(owner, nil, wishFor)
+		assert.NilError(t, err)
+	}
+	fulfilled := app.checkWishes(items)
+	assert.Equal(t, len(fulfilled), 1)
+	assert.Equal(t, fulfilled[0].Owner, "alice")
+
+	page := func(name string) string {
+		token, err := store.createSession(store.users[name])
+		assert.NilError(t, err)
+		request := httptest.NewRequest("GET", "/wishlist", nil)
+		request.AddCookie(&http.Cookie{Name: sessionCookieName, Value: token})
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, recorder.Code, 200)
+		return recorder.Body.String()
+	}
+	assert.Assert(t, strings.Contains(page("alice"), "You already own these (1)"))
+	bobPage := page("bob")
+	assert.Assert(t, strings.Contains(bobPage, "Still wanted (1)"))
+	assert.Assert(t, !strings.Contains(bobPage, "SharedBank"))
+
+	// Bob removing his wish leaves Alice's alone.
+	token, err := store.createSession(store.users["bob"])
+	assert.NilError(t, err)
+	form := url.Values{"action": {"remove"}, "query": {"Cloak"}, "exact": {"1"}}
+	request := httptest.NewRequest("POST", "/wishlist", strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.AddCookie(&http.Cookie{Name: sessionCookieName, Value: token})
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	wishes, err := app.loadWishes("bob")
+	assert.NilError(t, err)
+	assert.Equal(t, len(wishes), 0)
+	wishes, err = app.loadWishes("alice")
+	assert.NilError(t, err)
+	assert.Equal(t, len(wishes), 1)
+}